@@ -43,7 +43,7 @@ func main() {
 	store := storage.NewSlotStore(rdb)
 
 	// Services
-	simulateService := service.NewSimulateService(logger, store)
+	simulateService := service.NewSimulateService(cfg, logger, store)
 	mazeService := maze.NewMazeService(cfg, logger)
 
 	// Handlers
@@ -73,6 +73,9 @@ func main() {
 		simulate := public.Group("/simulate")
 		{
 			simulate.POST("/run", simulateHandler.Simulate)
+			simulate.POST("/replay", simulateHandler.Replay)
+			simulate.POST("/compare", simulateHandler.Compare)
+			simulate.POST("/sweep", simulateHandler.Sweep)
 		}
 		leetcode := public.Group("/leetcode")
 		{
@@ -6,9 +6,17 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/scheduler"
 	"gopkg.in/yaml.v3"
 )
 
+// MaxHybridWeight bounds alpha/beta/gamma, same limit as the request binding
+const MaxHybridWeight = 1000
+
+// MaxSweepWorkers bounds simulation.sweep_workers
+const MaxSweepWorkers = 64
+
 type Log struct {
 	Level      string `yaml:"level" json:"level" validate:"required,oneof=debug info warn error fatal"` // Required with validation
 	Format     string `yaml:"format" json:"format" validate:"omitempty,oneof=json text"`                // Optional: json (default), text
@@ -40,12 +48,96 @@ type CorsConfig struct {
 	AllowedOrigins []string `yaml:"allowed_origins" json:"allowed_origins"`
 }
 
+// HybridConfig holds the default weights of the hybrid score
+type HybridConfig struct {
+	Alpha float64 `yaml:"alpha" json:"alpha"` // priority weight
+	Beta  float64 `yaml:"beta" json:"beta"`   // waiting time weight
+	Gamma float64 `yaml:"gamma" json:"gamma"` // fairness penalty
+
+	WeightedDebt bool    `yaml:"weighted_debt" json:"weighted_debt"`   // charge 1/weight per allocation
+	DebtDecay    string  `yaml:"debt_decay" json:"debt_decay"`         // none (default), exponential, window
+	DebtHalfLife float64 `yaml:"debt_half_life" json:"debt_half_life"` // ticks, for exponential
+	DebtWindow   int     `yaml:"debt_window" json:"debt_window"`       // ticks, for window
+}
+
+// ClientClassConfig is one tier of the simulated client population
+type ClientClassConfig struct {
+	Name        string  `yaml:"name" json:"name"`
+	Share       float64 `yaml:"share" json:"share"`               // share of clients, normalised over all classes
+	Weight      float64 `yaml:"weight" json:"weight"`             // fairness weight
+	Priority    int     `yaml:"priority" json:"priority"`         // base priority, 1 = highest
+	MinRequests int     `yaml:"min_requests" json:"min_requests"` // requests per client, inclusive
+	MaxRequests int     `yaml:"max_requests" json:"max_requests"`
+	TTL         int     `yaml:"ttl" json:"ttl"`           // ticks from arrival to the end of service, later is a miss, 0 = never
+	MinSize     int     `yaml:"min_size" json:"min_size"` // units per request, default 1
+	MaxSize     int     `yaml:"max_size" json:"max_size"` // default min_size
+	// Pools the class may be served from, target first then substitutes
+	Pools []string `yaml:"pools" json:"pools"`
+	// Demand is the resource vector of one request in multi-resource simulations
+	Demand map[string]int `yaml:"demand" json:"demand"`
+	// service time in ticks, default 1
+	MinService int `yaml:"min_service" json:"min_service"`
+	MaxService int `yaml:"max_service" json:"max_service"`
+	// retry policy of rejected, shed or dropped requests, default no retry
+	MaxAttempts  int     `yaml:"max_attempts" json:"max_attempts"`
+	RetryBackoff int     `yaml:"retry_backoff" json:"retry_backoff"` // ticks, doubles per attempt
+	RetryJitter  float64 `yaml:"retry_jitter" json:"retry_jitter"`   // 0-1, share of the backoff
+	// patience in ticks before a queued request reneges, 0 = forever
+	MinPatience int `yaml:"min_patience" json:"min_patience"`
+	MaxPatience int `yaml:"max_patience" json:"max_patience"`
+}
+
+type SimulationConfig struct {
+	Hybrid HybridConfig `yaml:"hybrid" json:"hybrid"`
+	// Classes is the default client class catalog, empty keeps vip/paid/free
+	Classes []ClientClassConfig `yaml:"classes" json:"classes"`
+	// Expr is the default scoring formula of policy=expr
+	Expr string `yaml:"expr" json:"expr"`
+	// SweepWorkers bounds the simulations a parameter sweep runs at once
+	SweepWorkers int `yaml:"sweep_workers" json:"sweep_workers"`
+	// TieBreak orders equal priorities in priority/priority_np: arrival (default) or latest
+	TieBreak string `yaml:"tie_break" json:"tie_break"`
+}
+
+// ClassCatalog converts the configured classes, falling back to vip/paid/free
+func (c SimulationConfig) ClassCatalog() []models.ClientClass {
+	if len(c.Classes) == 0 {
+		return scheduler.DefaultClasses()
+	}
+
+	catalog := make([]models.ClientClass, 0, len(c.Classes))
+	for _, class := range c.Classes {
+		catalog = append(catalog, models.ClientClass{
+			Name:         class.Name,
+			Share:        class.Share,
+			Weight:       class.Weight,
+			Priority:     class.Priority,
+			MinRequests:  class.MinRequests,
+			MaxRequests:  class.MaxRequests,
+			TTL:          class.TTL,
+			MinSize:      class.MinSize,
+			MaxSize:      class.MaxSize,
+			Pools:        class.Pools,
+			Demand:       class.Demand,
+			MinService:   class.MinService,
+			MaxService:   class.MaxService,
+			MaxAttempts:  class.MaxAttempts,
+			RetryBackoff: class.RetryBackoff,
+			RetryJitter:  class.RetryJitter,
+			MinPatience:  class.MinPatience,
+			MaxPatience:  class.MaxPatience,
+		})
+	}
+	return catalog
+}
+
 type Config struct {
 	Log         Log               `yaml:"log" json:"log"`
 	RateLimit   RateLimit         `yaml:"rate_limit" json:"rate_limit"`
 	RedisConfig RedisConfig       `yaml:"redis" json:"redis"`
 	MazeService MazeServiceConfig `yaml:"maze_service" json:"maze_service"`
 	Cors        CorsConfig        `yaml:"cors" json:"cors"`
+	Simulation  SimulationConfig  `yaml:"simulation" json:"simulation"`
 }
 
 // LoadFromFile loads configuration from a specific YAML file
@@ -141,6 +233,53 @@ func validate(config *Config) error {
 	if config.Log.MaxAge <= 0 {
 		config.Log.MaxAge = 30 // Default 30 days
 	}
+
+	// Hybrid weights: a missing section falls back to the original 10/1/2
+	hybrid := &config.Simulation.Hybrid
+	if hybrid.Alpha == 0 && hybrid.Beta == 0 && hybrid.Gamma == 0 {
+		hybrid.Alpha, hybrid.Beta, hybrid.Gamma = 10, 1, 2
+	}
+	weights := []struct {
+		name  string
+		value float64
+	}{{"alpha", hybrid.Alpha}, {"beta", hybrid.Beta}, {"gamma", hybrid.Gamma}}
+	for _, w := range weights {
+		if w.value < 0 || w.value > MaxHybridWeight {
+			return fmt.Errorf("invalid simulation.hybrid.%s: %v (valid range: 0-%d)", w.name, w.value, MaxHybridWeight)
+		}
+	}
+	debt := scheduler.HybridConfig{
+		DebtDecay:    scheduler.DebtDecay(hybrid.DebtDecay),
+		DebtHalfLife: hybrid.DebtHalfLife,
+		DebtWindow:   hybrid.DebtWindow,
+	}
+	if err := debt.Validate(); err != nil {
+		return fmt.Errorf("invalid simulation.hybrid: %w", err)
+	}
+
+	// Default formula reproduces the default hybrid weights
+	if strings.TrimSpace(config.Simulation.Expr) == "" {
+		config.Simulation.Expr = "priority*10 + wait - debt*2"
+	}
+
+	if config.Simulation.TieBreak == "" {
+		config.Simulation.TieBreak = string(scheduler.TieBreakArrival)
+	}
+	if err := (scheduler.PriorityConfig{TieBreak: scheduler.TieBreak(config.Simulation.TieBreak)}).Validate(); err != nil {
+		return fmt.Errorf("invalid simulation.tie_break: %w", err)
+	}
+
+	if config.Simulation.SweepWorkers == 0 {
+		config.Simulation.SweepWorkers = 4
+	}
+	if config.Simulation.SweepWorkers < 1 || config.Simulation.SweepWorkers > MaxSweepWorkers {
+		return fmt.Errorf("invalid simulation.sweep_workers: %d (valid range: 1-%d)", config.Simulation.SweepWorkers, MaxSweepWorkers)
+	}
+
+	// Client class catalog, cùng rule với catalog gửi theo request
+	if err := scheduler.ValidateClasses(config.Simulation.ClassCatalog()); err != nil {
+		return fmt.Errorf("invalid simulation.classes: %w", err)
+	}
 	return nil
 }
 func overrideFromEnv(cfg *Config) {
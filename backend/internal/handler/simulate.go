@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/service"
@@ -37,7 +40,53 @@ func (h *SimulateHandler) Simulate(c *gin.Context) {
 
 	events, err := h.service.RunSimulation(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewAPIError(http.StatusInternalServerError, err.Error()))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+	})
+}
+
+// Replay runs a simulation on an uploaded CSV/NDJSON trace (multipart field "trace")
+func (h *SimulateHandler) Replay(c *gin.Context) {
+
+	var input models.ReplayRequest
+
+	if err := c.ShouldBind(&input); err != nil {
+		apiErr := utils.FormatValidationError(err)
+		c.JSON(apiErr.Code, apiErr)
+		return
+	}
+
+	file, err := c.FormFile("trace")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.NewAPIError(http.StatusBadRequest, "trace file is required"))
+		return
+	}
+	if input.Format == "" {
+		switch strings.ToLower(filepath.Ext(file.Filename)) {
+		case ".csv":
+			input.Format = "csv"
+		case ".ndjson", ".jsonl":
+			input.Format = "ndjson"
+		default:
+			c.JSON(http.StatusBadRequest, utils.NewAPIError(http.StatusBadRequest, "cannot detect trace format, set format=csv or format=ndjson"))
+			return
+		}
+	}
+
+	trace, err := file.Open()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	defer trace.Close()
+
+	events, err := h.service.RunReplay(input, trace)
+	if err != nil {
+		h.handleError(c, err)
 		return
 	}
 
@@ -45,3 +94,54 @@ func (h *SimulateHandler) Simulate(c *gin.Context) {
 		"events": events,
 	})
 }
+
+// Compare runs several policies on one generated workload and returns their metrics side by side
+func (h *SimulateHandler) Compare(c *gin.Context) {
+
+	var input models.CompareRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apiErr := utils.FormatValidationError(err)
+		c.JSON(apiErr.Code, apiErr)
+		return
+	}
+
+	result, err := h.service.Compare(input)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Sweep runs policy=hybrid over a grid of weights and seeds and marks the Pareto frontier
+func (h *SimulateHandler) Sweep(c *gin.Context) {
+
+	var input models.SweepRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apiErr := utils.FormatValidationError(err)
+		c.JSON(apiErr.Code, apiErr)
+		return
+	}
+
+	result, err := h.service.Sweep(input)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SimulateHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, utils.NewAPIError(http.StatusBadRequest, err.Error()))
+
+	default:
+		h.logger.Errorf("simulation failed: %+v", err)
+		c.JSON(http.StatusInternalServerError, utils.NewAPIError(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+// Metrics summarises the outcome of one simulation run
+type Metrics struct {
+	Classes map[string]ClassMetrics `json:"classes"`
+	// FairnessIndex is Jain's index over per-client allocation divided by
+	// client weight: 1 = every client got its weighted share, 1/n = one client got all
+	FairnessIndex       float64 `json:"fairness_index"`
+	StarvationThreshold int     `json:"starvation_threshold"`
+	// Starved counts requests that waited longer than StarvationThreshold ticks,
+	// whether they were served in the end or not
+	Starved int `json:"starved"`
+	// MaxWait is the longest time any request spent queued, in ticks
+	MaxWait int `json:"max_wait"`
+	// Attempts counts every request the scheduler saw, retries included, and
+	// LoadAmplification is Attempts / original requests (1 = no retries)
+	Attempts          int     `json:"attempts"`
+	Retries           int     `json:"retries"`
+	LoadAmplification float64 `json:"load_amplification"`
+}
+
+type ClassMetrics struct {
+	Requests  int `json:"requests"` // original requests, retries not included
+	Retries   int `json:"retries"`  // retry attempts
+	Allocated int `json:"allocated"`
+	Rejected  int `json:"rejected"` // no capacity left
+	Dropped   int `json:"dropped"`  // expired in the queue
+	Shed      int `json:"shed"`     // turned away by admission control
+	Reneged   int `json:"reneged"`  // client gave up waiting
+	Units     int `json:"units"`    // units granted
+	// MeanResponse is the mean ticks from arrival to completion and
+	// MeanSlowdown the mean response / service time, over allocated requests
+	MeanResponse float64 `json:"mean_response"`
+	MeanSlowdown float64 `json:"mean_slowdown"`
+	// DeadlineMissRatio is the share of requests with a deadline that no
+	// attempt finished by its deadline (never served or completed late),
+	// 0 when the class has no deadlines
+	DeadlineMissRatio float64 `json:"deadline_miss_ratio"`
+	// CapacityShare is the fraction of the simulation capacity the class got
+	// (the dominant share of its demand in multi-resource runs)
+	CapacityShare float64 `json:"capacity_share"`
+	// wait in ticks from arrival to allocation, over allocated requests
+	WaitP50 float64 `json:"wait_p50"`
+	WaitP95 float64 `json:"wait_p95"`
+	WaitP99 float64 `json:"wait_p99"`
+}
+
+// CompareResponse holds one result per policy, all run on the same workload
+type CompareResponse struct {
+	Seed          int64           `json:"seed"`
+	TotalRequests int             `json:"total_requests"`
+	Classes       []ClientClass   `json:"classes"`
+	Results       []CompareResult `json:"results"`
+}
+
+type CompareResult struct {
+	Policy       string  `json:"policy"`
+	SimulationID string  `json:"simulation_id"`
+	Metrics      Metrics `json:"metrics"`
+}
+
+// SweepResponse holds one result per parameter combination, averaged over the seeds
+type SweepResponse struct {
+	Runs    int           `json:"runs"`
+	Seeds   []int64       `json:"seeds"`
+	Results []SweepResult `json:"results"`
+}
+
+type SweepResult struct {
+	Hybrid        HybridWeights `json:"hybrid"`
+	FairnessIndex float64       `json:"fairness_index"`
+	// PrioritySatisfaction is the allocated share of all requests weighted
+	// by 1/Priority, so serving a VIP counts more than serving a free client
+	PrioritySatisfaction float64 `json:"priority_satisfaction"`
+	Starved              float64 `json:"starved"`
+	// Pareto is true when no other combination is at least as good on both
+	// fairness_index and priority_satisfaction and better on one
+	Pareto bool `json:"pareto"`
+}
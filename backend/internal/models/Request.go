@@ -1,21 +1,221 @@
 package models
 
 type SimulationRequest struct {
-	TotalClients  int    `json:"total_clients" binding:"required,gt=0" validate:"gt=0"`
-	TotalVouchers int    `json:"total_vouchers" binding:"required,gt=0" validate:"gt=0"`
-	Seed          int64  `json:"seed"`
-	Policy        string `json:"policy" binding:"required,oneof=fifo priority lottery hybrid" validate:"oneof=fifo priority lottery hybrid"`
+	SimulationWorkload
+	Policy string `json:"policy" binding:"required,oneof=fifo priority priority_np lottery hybrid knapsack drf expr edf llf sjf srpt" validate:"oneof=fifo priority priority_np lottery hybrid knapsack drf expr edf llf sjf srpt"`
+	// Hybrid overrides the configured hybrid weights, only used by policy=hybrid
+	Hybrid *HybridParams `json:"hybrid,omitempty"`
+	// Expr overrides the configured scoring formula, only used by policy=expr
+	Expr string `json:"expr,omitempty" binding:"omitempty,max=512"`
+	// TieBreak orders requests of equal priority: arrival (earliest first) or
+	// latest, only used by policy=priority and priority_np
+	TieBreak string `json:"tie_break,omitempty" binding:"omitempty,oneof=arrival latest"`
 }
+
+// CompareRequest runs several policies on one generated workload
+type CompareRequest struct {
+	SimulationWorkload
+	Policies []string `json:"policies" binding:"required,min=1,max=16,unique,dive,oneof=fifo priority priority_np lottery hybrid knapsack drf expr edf llf sjf srpt"`
+	// Hybrid overrides the configured hybrid weights, only used by policy=hybrid
+	Hybrid *HybridParams `json:"hybrid,omitempty"`
+	// Expr overrides the configured scoring formula, only used by policy=expr
+	Expr string `json:"expr,omitempty" binding:"omitempty,max=512"`
+}
+
+// SweepRequest runs policy=hybrid over a grid of parameters and seeds
+type SweepRequest struct {
+	SimulationWorkload
+	Seeds []int64 `json:"seeds" binding:"required,min=1,max=20,unique"`
+	// Hybrid is the base configuration, parameters missing from Grid keep its value
+	Hybrid *HybridParams `json:"hybrid,omitempty"`
+	Grid   SweepGrid     `json:"grid"`
+}
+
+// SweepGrid lists the values tried for each hybrid parameter
+type SweepGrid struct {
+	Alpha        *SweepRange `json:"alpha,omitempty"`
+	Beta         *SweepRange `json:"beta,omitempty"`
+	Gamma        *SweepRange `json:"gamma,omitempty"`
+	DebtHalfLife *SweepRange `json:"debt_half_life,omitempty"`
+	DebtWindow   *SweepRange `json:"debt_window,omitempty"`
+	WeightedDebt []bool      `json:"weighted_debt,omitempty" binding:"omitempty,max=2,unique"`
+	DebtDecay    []string    `json:"debt_decay,omitempty" binding:"omitempty,max=3,unique,dive,oneof=none exponential window"`
+}
+
+// SweepRange is either an explicit list of values or from..to by step
+type SweepRange struct {
+	Values []float64 `json:"values,omitempty" binding:"omitempty,max=100,dive,gte=0,lte=1000"`
+	From   float64   `json:"from" binding:"gte=0,lte=1000"`
+	To     float64   `json:"to" binding:"gtefield=From,lte=1000"`
+	Step   float64   `json:"step" binding:"required_without=Values,omitempty,gt=0"`
+}
+
+// SimulationWorkload is the policy-independent part of a simulation:
+// what gets generated and what capacity it competes for
+type SimulationWorkload struct {
+	TotalClients  int   `json:"total_clients" binding:"required,gt=0" validate:"gt=0"`
+	TotalVouchers int   `json:"total_vouchers" binding:"required_without_all=Pools Resources,gte=0" validate:"gte=0"` // ignored when pools or resources are set
+	Seed          int64 `json:"seed"`
+	// Service sets the per-tick capacity, default is 1 server serving 1 request/tick
+	Service *ServiceParams `json:"service,omitempty"`
+	// Classes replaces the configured client class catalog for this run
+	Classes []ClientClass `json:"classes,omitempty" binding:"omitempty,max=50,dive"`
+	// Arrival selects the arrival process, default is a gaussian burst around tick 50
+	Arrival *ArrivalParams `json:"arrival,omitempty"`
+	// TTL overrides ClientClass.TTL per class name
+	TTL map[string]int `json:"ttl,omitempty" binding:"omitempty,dive,keys,required,endkeys,gte=1"`
+	// WaitSnapshotEvery emits a wait event for every queued request each N ticks, 0 = off
+	WaitSnapshotEvery int `json:"wait_snapshot_every,omitempty" binding:"omitempty,gte=1"`
+	// Fill decides what happens when a request asks for more units than are left:
+	// all_or_nothing (default) rejects it, partial grants what is left
+	Fill string `json:"fill,omitempty" binding:"omitempty,oneof=all_or_nothing partial"`
+	// Pools splits the vouchers into named inventories, default is a single
+	// "default" pool of total_vouchers
+	Pools []Pool `json:"pools,omitempty" binding:"omitempty,max=32,dive"`
+	// Resources is the capacity vector (e.g. cpu, gpu, memory). When set,
+	// requests take their class demand vector instead of pool units
+	Resources map[string]int `json:"resources,omitempty" binding:"omitempty,max=16,dive,keys,required,max=64,endkeys,gte=1"`
+	// StarvationThreshold is the wait in ticks above which a request counts
+	// as starved in the metrics, default 100
+	StarvationThreshold int `json:"starvation_threshold,omitempty" binding:"omitempty,gte=1"`
+	// Admission bounds the queue and picks who is shed when it is full, default unbounded
+	Admission *AdmissionParams `json:"admission,omitempty"`
+}
+
+// AdmissionParams is the load shedding of the simulated gateway
+type AdmissionParams struct {
+	MaxQueue int    `json:"max_queue" binding:"required,gte=1,lte=1000000"`
+	Policy   string `json:"policy,omitempty" binding:"omitempty,oneof=tail_drop drop_lowest early_drop"` // default tail_drop
+	// early_drop: queue length where shedding starts, default max_queue/2
+	MinQueue int `json:"min_queue,omitempty" binding:"omitempty,gte=1,ltfield=MaxQueue"`
+	// early_drop: shed probability multiplier per class, default 1, 0 = never shed early
+	ClassWeights map[string]float64 `json:"class_weights,omitempty" binding:"omitempty,dive,keys,required,endkeys,gte=0,lte=100"`
+}
+
+// Pool is a named inventory of one voucher type (SKU)
+type Pool struct {
+	Name  string `json:"name" binding:"required,max=64"`
+	Slots int    `json:"slots" binding:"gte=1"`
+}
+
+// ReplayRequest is the multipart form sent with an uploaded trace file
+type ReplayRequest struct {
+	TotalVouchers       int    `form:"total_vouchers" json:"total_vouchers" binding:"required,gt=0"`
+	Seed                int64  `form:"seed" json:"seed"`
+	Policy              string `form:"policy" json:"policy" binding:"required,oneof=fifo priority priority_np lottery hybrid knapsack drf expr edf llf sjf srpt"`
+	Format              string `form:"format" json:"format" binding:"omitempty,oneof=csv ndjson"` // default: from file extension
+	TickMillis          int    `form:"tick_ms" json:"tick_ms" binding:"omitempty,gt=0"`           // tick length for timestamped traces, default 1ms
+	WaitSnapshotEvery   int    `form:"wait_snapshot_every" json:"wait_snapshot_every" binding:"omitempty,gte=1"`
+	Fill                string `form:"fill" json:"fill" binding:"omitempty,oneof=all_or_nothing partial"`
+	StarvationThreshold int    `form:"starvation_threshold" json:"starvation_threshold" binding:"omitempty,gte=1"`
+	Expr                string `form:"expr" json:"expr" binding:"omitempty,max=512"`
+	MaxQueue            int    `form:"max_queue" json:"max_queue" binding:"omitempty,gte=1,lte=1000000"`
+	Admission           string `form:"admission" json:"admission" binding:"omitempty,oneof=tail_drop drop_lowest early_drop"`
+}
+
+type ServiceParams struct {
+	Rate     int              `json:"rate,omitempty" binding:"omitempty,gte=1,lte=100000"`  // requests per server per tick
+	Servers  int              `json:"servers,omitempty" binding:"omitempty,gte=1,lte=1000"` // parallel servers
+	Schedule []CapacityWindow `json:"schedule,omitempty" binding:"omitempty,dive"`
+}
+
+// CapacityWindow overrides the per-server rate from FromTick onwards
+type CapacityWindow struct {
+	FromTick int `json:"from_tick" binding:"gte=0"`
+	Rate     int `json:"rate" binding:"gte=0,lte=100000"`
+}
+
+// ArrivalParams selects an arrival model; only the fields of that model are read
+type ArrivalParams struct {
+	Model string `json:"model" binding:"required,oneof=gaussian uniform waves diurnal poisson pareto"`
+
+	Center float64 `json:"center,omitempty"`                // gaussian: burst center tick (default 50)
+	Sigma  float64 `json:"sigma,omitempty" binding:"gte=0"` // gaussian: burst width (default 10)
+
+	Start int `json:"start,omitempty" binding:"gte=0"` // uniform, diurnal, poisson, pareto: first tick
+	End   int `json:"end,omitempty" binding:"gte=0"`   // uniform, diurnal: last tick
+
+	Waves []ArrivalWave `json:"waves,omitempty" binding:"omitempty,max=20,dive"` // waves
+
+	Period    int     `json:"period,omitempty" binding:"gte=0"`          // diurnal: ticks per cycle
+	Peak      int     `json:"peak,omitempty"`                            // diurnal: tick of the daily peak
+	Amplitude float64 `json:"amplitude,omitempty" binding:"gte=0,lte=1"` // diurnal: 0 = flat, 1 = no traffic at trough
+
+	Rate float64 `json:"rate,omitempty" binding:"gte=0"` // poisson: mean arrivals per tick
+
+	Shape float64 `json:"shape,omitempty" binding:"gte=0"` // pareto: tail index, lower = heavier
+	Scale float64 `json:"scale,omitempty" binding:"gte=0"` // pareto: minimum gap in ticks
+}
+
+// ArrivalWave is one gaussian burst of the waves model
+type ArrivalWave struct {
+	Center float64 `json:"center"`
+	Sigma  float64 `json:"sigma" binding:"gt=0"`
+	Weight float64 `json:"weight" binding:"gt=0"` // relative share of requests
+}
+
+// HybridParams is an optional override, unset fields keep the config default
+type HybridParams struct {
+	Alpha *float64 `json:"alpha,omitempty" binding:"omitempty,gte=0,lte=1000"`
+	Beta  *float64 `json:"beta,omitempty" binding:"omitempty,gte=0,lte=1000"`
+	Gamma *float64 `json:"gamma,omitempty" binding:"omitempty,gte=0,lte=1000"`
+
+	WeightedDebt *bool    `json:"weighted_debt,omitempty"`
+	DebtDecay    *string  `json:"debt_decay,omitempty" binding:"omitempty,oneof=none exponential window"`
+	DebtHalfLife *float64 `json:"debt_half_life,omitempty" binding:"omitempty,gt=0"`
+	DebtWindow   *int     `json:"debt_window,omitempty" binding:"omitempty,gt=0"`
+}
+
+// ClientClass is one tier of the client population (vip, paid, free, enterprise, ...)
+type ClientClass struct {
+	Name        string  `json:"name" binding:"required"`
+	Share       float64 `json:"share" binding:"gt=0"`         // share of clients, normalised over the catalog
+	Weight      float64 `json:"weight" binding:"gt=0"`        // fairness weight
+	Priority    int     `json:"priority" binding:"gte=1"`     // base priority, 1 = cao nhất
+	MinRequests int     `json:"min_requests" binding:"gte=1"` // requests per client, inclusive
+	MaxRequests int     `json:"max_requests" binding:"gtefield=MinRequests,lte=100"`
+	TTL         int     `json:"ttl,omitempty" binding:"omitempty,gte=1"`                           // ticks from arrival to the end of service, later is a miss, 0 = never
+	MinSize     int     `json:"min_size,omitempty" binding:"omitempty,gte=1"`                      // units per request, uniform in [min_size, max_size], default 1
+	MaxSize     int     `json:"max_size,omitempty" binding:"omitempty,gtefield=MinSize,lte=10000"` // default min_size
+	// Pools the class may be served from, in order of preference:
+	// the first is the target, the rest are substitutes. Default: the first pool
+	Pools []string `json:"pools,omitempty" binding:"omitempty,dive,required"`
+	// Demand is the resource vector of one request, only used with resources
+	Demand map[string]int `json:"demand,omitempty" binding:"omitempty,dive,keys,required,endkeys,gte=0"`
+	// service time in ticks, uniform in [min_service, max_service], default 1
+	MinService int `json:"min_service,omitempty" binding:"omitempty,gte=1"`
+	MaxService int `json:"max_service,omitempty" binding:"omitempty,gtefield=MinService,lte=10000"`
+	// retry policy: a rejected, shed or dropped request is tried again up to
+	// max_attempts in total, retry_backoff*2^(attempt-1) ticks later ± retry_jitter
+	MaxAttempts  int     `json:"max_attempts,omitempty" binding:"omitempty,gte=1,lte=10"`
+	RetryBackoff int     `json:"retry_backoff,omitempty" binding:"omitempty,gte=1,lte=10000"` // default 1
+	RetryJitter  float64 `json:"retry_jitter,omitempty" binding:"gte=0,lte=1"`                // share of the backoff
+	// patience in ticks before a queued request gives up, uniform in
+	// [min_patience, max_patience], 0 = waits forever
+	MinPatience int `json:"min_patience,omitempty" binding:"omitempty,gte=1"`
+	MaxPatience int `json:"max_patience,omitempty" binding:"omitempty,gtefield=MinPatience,lte=100000"`
+}
+
 type Client struct {
 	ID     int
-	Class  string  // vip / paid / free
+	Class  string  // ClientClass.Name
 	Weight float64 // fairness weight
 }
 type Request struct {
 	ID        int
 	ClientID  int
-	Priority  int // 1 = cao nhất
-	ArrivalAt int // logical time (tick)
+	Priority  int            // 1 = cao nhất
+	ArrivalAt int            // logical time (tick)
+	Deadline  int            // last tick of service to finish in time, 0 = no deadline
+	Size      int            // units requested, >= 1
+	Pools     []string       // acceptable pools in order of preference, empty = any
+	Resources map[string]int // demand vector, nil = Size units of a pool
+	// ServiceTime is how many ticks the request holds a server, 0 = 1 tick
+	ServiceTime int
+	Patience    int // ticks the client waits in the queue before reneging, 0 = forever
+	// RetryOf is the ID of the original request this one retries, 0 = first attempt
+	RetryOf int
+	Attempt int // 1-based, 0 = 1
 }
 type RuntimeRequest struct {
 	Request
@@ -4,12 +4,54 @@ type SimulateResponse struct {
 	Simulation   Simulation `json:"simulation"`
 	ArrivalOrder []ClientArrival
 	Events       []Event `json:"events"`
+	// DropRates is the share of requests per class that expired before being served
+	DropRates map[string]float64 `json:"drop_rates,omitempty"`
+	// ShedRates is the share of requests per class turned away by admission control
+	ShedRates map[string]float64 `json:"shed_rates,omitempty"`
+	Pools     []PoolStats        `json:"pools"`
+	// DominantShares is the final dominant share of every client (by ID),
+	// only set for multi-resource simulations
+	DominantShares map[int]float64 `json:"dominant_shares,omitempty"`
+	Metrics        Metrics         `json:"metrics"`
 }
+
+// PoolStats is the allocation outcome of one voucher pool
+type PoolStats struct {
+	Name      string `json:"name"`
+	Slots     int    `json:"slots"`
+	Allocated int    `json:"allocated"` // units handed out
+	Remaining int    `json:"remaining"`
+	Requests  int    `json:"requests"` // requests served from this pool
+	// Substitutes counts requests served here although they preferred another pool
+	Substitutes int `json:"substitutes"`
+}
+
+// Event actions
+const (
+	EventEnqueue   = "enqueue"   // request arrived and joined the queue
+	EventWait      = "wait"      // request still queued, Score is its current score
+	EventSelected  = "selected"  // scheduler picked the request
+	EventAllocated = "allocated" // selected and got a slot
+	EventRejected  = "rejected"  // no slot left: after selected, or straight from the queue once capacity ran out
+	EventDrop      = "drop"      // could no longer finish by its deadline, left the queue
+	EventPreempted = "preempted" // lost its server to a shorter request (srpt)
+	EventResumed   = "resumed"   // preempted request got a server again
+	EventCompleted = "completed" // finished its service time
+	EventShed      = "shed"      // turned away by admission control, queue full
+	EventReneged   = "reneged"   // client ran out of patience and left the queue
+)
+
 type Event struct {
-	Tick      int
-	RequestID int
-	ClientID  int
-	Priority  int
-	Score     float64
-	Action    string // enqueue | allocated | wait | drop
+	Tick      int     `json:"tick"`
+	RequestID int     `json:"request_id"`
+	ClientID  int     `json:"client_id"`
+	Priority  int     `json:"priority"`
+	Score     float64 `json:"score"`
+	Action    string  `json:"action"` // enqueue | wait | selected | allocated | rejected | drop | preempted | resumed | completed | shed | reneged
+	Server    int     `json:"server"`
+	Size      int     `json:"size"`               // units requested
+	Granted   int     `json:"granted,omitempty"`  // units allocated, < size on a partial fill
+	Pool      string  `json:"pool,omitempty"`     // pool the units came from
+	RetryOf   int     `json:"retry_of,omitempty"` // original request of a retry
+	Attempt   int     `json:"attempt,omitempty"`  // attempt number of a retry, >= 2
 }
@@ -9,4 +9,23 @@ type Simulation struct {
 	TotalRequests int       `json:"total_requests"`
 	Seed          int       `json:"seed"`
 	CreatedAt     time.Time `json:"created_at"`
+	// Hybrid is the effective weights used, only set for policy=hybrid
+	Hybrid *HybridWeights `json:"hybrid,omitempty"`
+	// Expr is the scoring formula used, only set for policy=expr
+	Expr string `json:"expr,omitempty"`
+	// Classes is the client class catalog the workload was generated from
+	Classes []ClientClass `json:"classes"`
+	// Resources is the capacity vector of a multi-resource simulation
+	Resources map[string]int `json:"resources,omitempty"`
+}
+
+type HybridWeights struct {
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+	Gamma float64 `json:"gamma"`
+
+	WeightedDebt bool    `json:"weighted_debt"`
+	DebtDecay    string  `json:"debt_decay"`
+	DebtHalfLife float64 `json:"debt_half_life,omitempty"`
+	DebtWindow   int     `json:"debt_window,omitempty"`
 }
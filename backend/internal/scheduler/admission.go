@@ -0,0 +1,78 @@
+package scheduler
+
+import "fmt"
+
+// AdmissionPolicy decides which request is shed when the queue is full
+type AdmissionPolicy string
+
+const (
+	AdmitTailDrop   AdmissionPolicy = "tail_drop"   // the arriving request is shed
+	AdmitDropLowest AdmissionPolicy = "drop_lowest" // the request least likely to be served next is shed, arriving or queued
+	AdmitEarlyDrop  AdmissionPolicy = "early_drop"  // arrivals are shed with a probability growing with the queue length
+)
+
+// AdmissionConfig bounds the queue of a run, like a gateway protecting
+// itself during a sale. The zero value admits everything.
+type AdmissionConfig struct {
+	MaxQueue int // queued requests, 0 = unbounded
+	Policy   AdmissionPolicy
+	// MinQueue is the queue length where early_drop starts shedding,
+	// the probability grows linearly up to 1 at MaxQueue
+	MinQueue int
+	// ClassWeights scales the early_drop probability per class name,
+	// default 1, 0 = never shed early
+	ClassWeights map[string]float64
+}
+
+// Normalize fills defaults and rejects inconsistent bounds
+func (c AdmissionConfig) Normalize() (AdmissionConfig, error) {
+	if c.MaxQueue < 0 || c.MinQueue < 0 {
+		return c, fmt.Errorf("max_queue and min_queue must be >= 0")
+	}
+	if c.MaxQueue == 0 {
+		return AdmissionConfig{}, nil
+	}
+	if c.Policy == "" {
+		c.Policy = AdmitTailDrop
+	}
+
+	switch c.Policy {
+	case AdmitTailDrop, AdmitDropLowest:
+	case AdmitEarlyDrop:
+		if c.MinQueue == 0 {
+			c.MinQueue = c.MaxQueue / 2
+		}
+		if c.MinQueue >= c.MaxQueue {
+			return c, fmt.Errorf("min_queue must be < max_queue")
+		}
+		for class, weight := range c.ClassWeights {
+			if weight < 0 {
+				return c, fmt.Errorf("class weight %s must be >= 0", class)
+			}
+		}
+	default:
+		return c, fmt.Errorf("unknown admission policy %q", c.Policy)
+	}
+	return c, nil
+}
+
+// earlyDropChance is the probability an arrival of class is shed with
+// queued requests already waiting
+func (c AdmissionConfig) earlyDropChance(queued int, class string) float64 {
+	if queued < c.MinQueue {
+		return 0
+	}
+	weight, ok := c.ClassWeights[class]
+	if !ok {
+		weight = 1
+	}
+	p := weight * float64(queued-c.MinQueue+1) / float64(c.MaxQueue-c.MinQueue+1)
+	return min(p, 1)
+}
+
+// lowerScoreFirstQueue marks queues that serve the lowest score first
+// (priority number, remaining service, slack, dominant share).
+// Other queues serve the highest score first.
+type lowerScoreFirstQueue interface {
+	lowerScoreFirst() bool
+}
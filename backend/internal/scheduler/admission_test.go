@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestAdmissionConfigNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AdmissionConfig
+		want    AdmissionConfig
+		wantErr string
+	}{
+		{
+			name: "unbounded",
+			cfg:  AdmissionConfig{Policy: AdmitEarlyDrop, MinQueue: 3},
+			want: AdmissionConfig{},
+		},
+		{
+			name: "tail drop by default",
+			cfg:  AdmissionConfig{MaxQueue: 10},
+			want: AdmissionConfig{MaxQueue: 10, Policy: AdmitTailDrop},
+		},
+		{
+			name: "early drop starts at half",
+			cfg:  AdmissionConfig{MaxQueue: 10, Policy: AdmitEarlyDrop},
+			want: AdmissionConfig{MaxQueue: 10, Policy: AdmitEarlyDrop, MinQueue: 5},
+		},
+		{
+			name:    "negative",
+			cfg:     AdmissionConfig{MaxQueue: -1},
+			wantErr: "max_queue and min_queue must be >= 0",
+		},
+		{
+			name:    "min not below max",
+			cfg:     AdmissionConfig{MaxQueue: 4, MinQueue: 4, Policy: AdmitEarlyDrop},
+			wantErr: "min_queue must be < max_queue",
+		},
+		{
+			name:    "negative class weight",
+			cfg:     AdmissionConfig{MaxQueue: 4, Policy: AdmitEarlyDrop, ClassWeights: map[string]float64{"free": -1}},
+			wantErr: "class weight free must be >= 0",
+		},
+		{
+			name:    "unknown policy",
+			cfg:     AdmissionConfig{MaxQueue: 4, Policy: "red"},
+			wantErr: `unknown admission policy "red"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.Normalize()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEarlyDropChance(t *testing.T) {
+	cfg := AdmissionConfig{MaxQueue: 7, MinQueue: 3, ClassWeights: map[string]float64{"vip": 0, "free": 2}}
+
+	tests := []struct {
+		queued int
+		class  string
+		want   float64
+	}{
+		{2, "paid", 0},
+		{3, "paid", 0.2},
+		{6, "paid", 0.8},
+		{7, "paid", 1},
+		{6, "vip", 0},
+		{3, "free", 0.4},
+		{6, "free", 1}, // chặn ở 1
+	}
+
+	for _, tt := range tests {
+		if got := cfg.earlyDropChance(tt.queued, tt.class); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("earlyDropChance(%d, %q) = %v, want %v", tt.queued, tt.class, got, tt.want)
+		}
+	}
+}
+
+func TestAdmission(t *testing.T) {
+	// 4 requests arrive together at a queue of 2, request 1 is served first
+	// only after all of them were admitted or shed
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Priority: 3, ServiceTime: 5},
+			{ID: 2, Priority: 3, ServiceTime: 5},
+			{ID: 3, Priority: 2, ServiceTime: 5},
+			{ID: 4, Priority: 1, ServiceTime: 5},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		admission AdmissionConfig
+		want      []string
+	}{
+		{
+			name: "unbounded",
+			want: []string{"0 select 4", "5 select 3", "10 select 1", "15 select 2"},
+		},
+		{
+			name:      "tail drop",
+			admission: AdmissionConfig{MaxQueue: 2, Policy: AdmitTailDrop},
+			want:      []string{"0 shed 3", "0 shed 4", "0 select 1", "5 select 2"},
+		},
+		{
+			// ties go to the latest arrival: 2 before 1
+			name:      "drop lowest",
+			admission: AdmissionConfig{MaxQueue: 2, Policy: AdmitDropLowest},
+			want:      []string{"0 shed 2", "0 shed 1", "0 select 4", "5 select 3"},
+		},
+		{
+			name:      "early drop with weight 0 is tail drop",
+			admission: AdmissionConfig{MaxQueue: 2, MinQueue: 1, Policy: AdmitEarlyDrop, ClassWeights: map[string]float64{"free": 0}},
+			want:      []string{"0 shed 3", "0 shed 4", "0 select 1", "5 select 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			w.Admission = tt.admission
+			decisions := NewPriorityStrategy(PriorityConfig{}).Schedule(w)
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionShed), tt.want)
+		})
+	}
+}
+
+func TestEarlyDrop(t *testing.T) {
+	var requests []models.Request
+	for id := 1; id <= 200; id++ {
+		requests = append(requests, models.Request{ID: id, ArrivalAt: id / 4, ServiceTime: 1})
+	}
+	run := func(weight float64) []string {
+		w := testWorkload(append([]models.Request(nil), requests...)...)
+		w.Admission = AdmissionConfig{MaxQueue: 8, MinQueue: 2, Policy: AdmitEarlyDrop, ClassWeights: map[string]float64{"free": weight}}
+		return timeline(NewFIFOStrategy().Schedule(w), ActionShed)
+	}
+
+	shed := run(1)
+	if !reflect.DeepEqual(shed, run(1)) {
+		t.Error("early_drop must be deterministic for a seed")
+	}
+	// 4 arrivals per tick, 1 served: the queue grows past min_queue and early
+	// drop sheds before it is full, weight 0 only sheds a full queue
+	if tail := run(0); len(shed) <= len(tail) {
+		t.Errorf("early drop shed %d, tail drop %d: want more with early drop", len(shed), len(tail))
+	}
+}
@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// maxArrivalTick caps sampled arrivals so heavy tails cannot stretch a run forever
+const maxArrivalTick = 1_000_000
+
+// ArrivalModel draws the arrival tick of one request.
+// Requests are drawn one by one in client order.
+type ArrivalModel interface {
+	Next(rng *rand.Rand) int
+}
+
+// arrivalProcess is implemented by models whose arrivals depend on each
+// other (inter-arrival times). The whole timeline is drawn at once and then
+// shuffled over the requests, so arrival order does not follow client ID.
+type arrivalProcess interface {
+	Timeline(rng *rand.Rand, n int) []int
+}
+
+// NewArrivalModel builds the model selected in the request, nil = default burst
+func NewArrivalModel(p *models.ArrivalParams) (ArrivalModel, error) {
+	if p == nil {
+		return defaultArrival(), nil
+	}
+
+	switch p.Model {
+	case "gaussian":
+		m := defaultArrival()
+		if p.Center != 0 {
+			m.center = p.Center
+		}
+		if p.Sigma != 0 {
+			m.sigma = p.Sigma
+		}
+		return m, nil
+
+	case "uniform":
+		if p.End < p.Start {
+			return nil, fmt.Errorf("uniform arrival needs end >= start")
+		}
+		return &uniformArrival{start: p.Start, end: p.End}, nil
+
+	case "waves":
+		if len(p.Waves) == 0 {
+			return nil, fmt.Errorf("waves arrival needs at least one wave")
+		}
+		m := &wavesArrival{}
+		for _, w := range p.Waves {
+			m.total += w.Weight
+			m.waves = append(m.waves, w)
+		}
+		return m, nil
+
+	case "diurnal":
+		if p.Period <= 0 || p.End <= p.Start {
+			return nil, fmt.Errorf("diurnal arrival needs period > 0 and end > start")
+		}
+		if p.Amplitude < 0 || p.Amplitude > 1 {
+			return nil, fmt.Errorf("diurnal amplitude must be within 0-1")
+		}
+		m := newDiurnalArrival(p.Start, min(p.End, maxArrivalTick+1), p.Period, p.Peak, p.Amplitude)
+		if len(m.cumulative) == 0 || m.cumulative[len(m.cumulative)-1] <= 0 {
+			return nil, fmt.Errorf("diurnal arrival has no traffic in [start, end)")
+		}
+		return m, nil
+
+	case "poisson":
+		if p.Rate <= 0 {
+			return nil, fmt.Errorf("poisson arrival needs rate > 0")
+		}
+		return &poissonArrival{start: p.Start, rate: p.Rate}, nil
+
+	case "pareto":
+		if p.Shape <= 0 || p.Scale <= 0 {
+			return nil, fmt.Errorf("pareto arrival needs shape > 0 and scale > 0")
+		}
+		return &paretoArrival{start: p.Start, shape: p.Shape, scale: p.Scale}, nil
+	}
+
+	return nil, fmt.Errorf("unknown arrival model %q", p.Model)
+}
+
+func clampTick(v float64) int {
+	switch {
+	case v < 0:
+		return 0
+	case v > maxArrivalTick:
+		return maxArrivalTick
+	}
+	return int(v)
+}
+
+// gaussianArrival is a single burst, by default centered on tick 50 with sigma 10
+type gaussianArrival struct {
+	center float64
+	sigma  float64
+}
+
+func defaultArrival() *gaussianArrival {
+	return &gaussianArrival{center: 50, sigma: 10}
+}
+
+func (m *gaussianArrival) Next(rng *rand.Rand) int {
+	return clampTick(rng.NormFloat64()*m.sigma + m.center)
+}
+
+// uniformArrival spreads requests evenly over [start, end]
+type uniformArrival struct {
+	start, end int
+}
+
+func (m *uniformArrival) Next(rng *rand.Rand) int {
+	return clampTick(float64(m.start + rng.Intn(m.end-m.start+1)))
+}
+
+// wavesArrival is a mix of gaussian bursts: a sale opening and follow-up pushes
+type wavesArrival struct {
+	waves []models.ArrivalWave
+	total float64 // tổng Weight
+}
+
+func (m *wavesArrival) Next(rng *rand.Rand) int {
+	roll := rng.Float64() * m.total
+
+	wave := m.waves[len(m.waves)-1]
+	for _, w := range m.waves {
+		roll -= w.Weight
+		if roll < 0 {
+			wave = w
+			break
+		}
+	}
+
+	return clampTick(rng.NormFloat64()*wave.Sigma + wave.Center)
+}
+
+// diurnalArrival follows rate(t) ∝ 1 + amplitude*cos(2π(t-peak)/period)
+// over [start, end), sampled by inverse CDF over the weight of every tick
+type diurnalArrival struct {
+	start      int
+	cumulative []float64 // cumulative[i] = tổng weight của start..start+i
+}
+
+func newDiurnalArrival(start, end, period, peak int, amplitude float64) *diurnalArrival {
+	m := &diurnalArrival{start: start}
+	var acc float64
+	for t := start; t < end; t++ {
+		phase := 2 * math.Pi * float64(t-peak) / float64(period)
+		acc += max(1+amplitude*math.Cos(phase), 0)
+		m.cumulative = append(m.cumulative, acc)
+	}
+	return m
+}
+
+func (m *diurnalArrival) Next(rng *rand.Rand) int {
+	roll := rng.Float64() * m.cumulative[len(m.cumulative)-1]
+	// tick đầu tiên có cumulative > roll, tick weight 0 không bao giờ được chọn
+	i := sort.Search(len(m.cumulative), func(i int) bool { return m.cumulative[i] > roll })
+	return clampTick(float64(m.start + i))
+}
+
+// poissonArrival is a Poisson process: exponential gaps with mean 1/rate ticks
+type poissonArrival struct {
+	start int
+	rate  float64 // mean arrivals per tick
+}
+
+func (m *poissonArrival) Next(rng *rand.Rand) int {
+	return m.start
+}
+
+func (m *poissonArrival) Timeline(rng *rand.Rand, n int) []int {
+	ticks := make([]int, n)
+	t := float64(m.start)
+	for i := range ticks {
+		t += rng.ExpFloat64() / m.rate
+		ticks[i] = clampTick(t)
+	}
+	return ticks
+}
+
+// paretoArrival has heavy-tailed gaps: scale / U^(1/shape)
+type paretoArrival struct {
+	start        int
+	shape, scale float64
+}
+
+func (m *paretoArrival) Next(rng *rand.Rand) int {
+	return m.start
+}
+
+func (m *paretoArrival) Timeline(rng *rand.Rand, n int) []int {
+	ticks := make([]int, n)
+	t := float64(m.start)
+	for i := range ticks {
+		u := 1 - rng.Float64() // (0, 1]
+		t += m.scale / math.Pow(u, 1/m.shape)
+		ticks[i] = clampTick(t)
+	}
+	return ticks
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestNewArrivalModelValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  models.ArrivalParams
+		wantErr string
+	}{
+		{"uniform", models.ArrivalParams{Model: "uniform", Start: 5, End: 5}, ""},
+		{"uniform reversed", models.ArrivalParams{Model: "uniform", Start: 5, End: 4}, "end >= start"},
+		{"waves empty", models.ArrivalParams{Model: "waves"}, "at least one wave"},
+		{"diurnal no period", models.ArrivalParams{Model: "diurnal", End: 10}, "period > 0"},
+		{"diurnal amplitude", models.ArrivalParams{Model: "diurnal", Period: 5, End: 10, Amplitude: 2}, "amplitude"},
+		// tick 1 duy nhất nằm đúng đáy
+		{"diurnal no traffic", models.ArrivalParams{Model: "diurnal", Start: 1, End: 2, Period: 2, Amplitude: 1}, "no traffic"},
+		{"diurnal narrow", models.ArrivalParams{Model: "diurnal", Start: 1, End: 3, Period: 2, Amplitude: 1}, ""},
+		{"poisson no rate", models.ArrivalParams{Model: "poisson"}, "rate > 0"},
+		{"pareto no scale", models.ArrivalParams{Model: "pareto", Shape: 1}, "scale > 0"},
+		{"unknown", models.ArrivalParams{Model: "zipf"}, "unknown arrival model"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewArrivalModel(&tt.params)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// arrivals draws the arrival ticks of a 1000-client workload, sorted
+func arrivals(t *testing.T, params *models.ArrivalParams) []int {
+	t.Helper()
+	model, err := NewArrivalModel(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	classes := []models.ClientClass{{Name: "a", Share: 1, Weight: 1, Priority: 1, MinRequests: 2, MaxRequests: 2}}
+	requests, _ := GenerateRequests(GenerateClients(3, 1000, classes), 3, classes, model)
+
+	ticks := make([]int, len(requests))
+	for i, r := range requests {
+		ticks[i] = r.ArrivalAt
+	}
+	return ticks
+}
+
+func mean(ticks []int) float64 {
+	sum := 0
+	for _, v := range ticks {
+		sum += v
+	}
+	return float64(sum) / float64(len(ticks))
+}
+
+func TestArrivalModels(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *models.ArrivalParams
+		check  func(t *testing.T, ticks []int)
+	}{
+		{
+			name:   "default burst",
+			params: nil,
+			check: func(t *testing.T, ticks []int) {
+				if m := mean(ticks); math.Abs(m-50) > 1 {
+					t.Errorf("mean = %v, want about 50", m)
+				}
+			},
+		},
+		{
+			name:   "uniform",
+			params: &models.ArrivalParams{Model: "uniform", Start: 10, End: 20},
+			check: func(t *testing.T, ticks []int) {
+				if ticks[0] != 10 || ticks[len(ticks)-1] != 20 {
+					t.Errorf("range = %d-%d, want 10-20", ticks[0], ticks[len(ticks)-1])
+				}
+			},
+		},
+		{
+			name: "waves",
+			params: &models.ArrivalParams{Model: "waves", Waves: []models.ArrivalWave{
+				{Center: 10, Sigma: 1, Weight: 1},
+				{Center: 200, Sigma: 1, Weight: 3},
+			}},
+			check: func(t *testing.T, ticks []int) {
+				early := 0
+				for _, v := range ticks {
+					if v < 100 {
+						early++
+					}
+				}
+				if share := float64(early) / float64(len(ticks)); math.Abs(share-0.25) > 0.05 {
+					t.Errorf("first wave share = %v, want about 0.25", share)
+				}
+			},
+		},
+		{
+			name:   "diurnal",
+			params: &models.ArrivalParams{Model: "diurnal", Start: 0, End: 100, Period: 100, Peak: 25, Amplitude: 1},
+			check: func(t *testing.T, ticks []int) {
+				// the trough at tick 75 gets almost nothing
+				peak, trough := 0, 0
+				for _, v := range ticks {
+					switch {
+					case v >= 15 && v < 35:
+						peak++
+					case v >= 65 && v < 85:
+						trough++
+					}
+				}
+				if ticks[len(ticks)-1] >= 100 || peak < 5*trough {
+					t.Errorf("peak = %d, trough = %d, last = %d", peak, trough, ticks[len(ticks)-1])
+				}
+			},
+		},
+		{
+			name:   "poisson",
+			params: &models.ArrivalParams{Model: "poisson", Start: 5, Rate: 2},
+			check: func(t *testing.T, ticks []int) {
+				// 2000 arrivals at 2 per tick span about 1000 ticks
+				if span := ticks[len(ticks)-1] - 5; math.Abs(float64(span)-1000) > 100 {
+					t.Errorf("span = %d ticks, want about 1000", span)
+				}
+			},
+		},
+		{
+			name:   "pareto",
+			params: &models.ArrivalParams{Model: "pareto", Start: 0, Shape: 1.5, Scale: 1},
+			check: func(t *testing.T, ticks []int) {
+				// every gap is at least scale
+				for i := 1; i < len(ticks); i++ {
+					if ticks[i] == ticks[i-1] {
+						t.Fatalf("two arrivals at tick %d", ticks[i])
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks := arrivals(t, tt.params)
+			if len(ticks) != 2000 {
+				t.Fatalf("got %d requests, want 2000", len(ticks))
+			}
+			tt.check(t, ticks)
+			if again := arrivals(t, tt.params); !reflect.DeepEqual(ticks, again) {
+				t.Error("same seed drew different arrivals")
+			}
+		})
+	}
+}
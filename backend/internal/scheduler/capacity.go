@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CapacityWindow sets the per-server service rate from FromTick onwards
+type CapacityWindow struct {
+	FromTick int
+	Rate     int
+}
+
+// ServiceConfig describes how many requests can be served per tick.
+// Every tick each of the Servers serves up to Rate requests, Schedule
+// overrides Rate from the given ticks (e.g. a sale opening at tick 100).
+type ServiceConfig struct {
+	Rate     int
+	Servers  int
+	Schedule []CapacityWindow
+}
+
+// DefaultServiceConfig is one server serving one request per tick
+func DefaultServiceConfig() ServiceConfig {
+	return ServiceConfig{Rate: 1, Servers: 1}
+}
+
+// Normalize fills defaults, sorts the schedule and rejects configurations
+// that could leave requests queued forever
+func (c ServiceConfig) Normalize() (ServiceConfig, error) {
+	if c.Rate == 0 {
+		c.Rate = 1
+	}
+	if c.Servers == 0 {
+		c.Servers = 1
+	}
+	if c.Rate < 0 || c.Servers < 0 {
+		return c, fmt.Errorf("service rate and servers must be positive")
+	}
+
+	schedule := make([]CapacityWindow, len(c.Schedule))
+	copy(schedule, c.Schedule)
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].FromTick < schedule[j].FromTick
+	})
+	for _, w := range schedule {
+		if w.FromTick < 0 || w.Rate < 0 {
+			return c, fmt.Errorf("capacity window must have from_tick >= 0 and rate >= 0")
+		}
+	}
+	if n := len(schedule); n > 0 && schedule[n-1].Rate == 0 {
+		return c, fmt.Errorf("last capacity window must have rate > 0")
+	}
+	c.Schedule = schedule
+
+	return c, nil
+}
+
+// capacityAt is the number of requests all servers can serve at tick
+func (c ServiceConfig) capacityAt(tick int) int {
+	rate := c.Rate
+	for _, w := range c.Schedule {
+		if w.FromTick > tick {
+			break
+		}
+		rate = w.Rate
+	}
+	return rate * c.Servers
+}
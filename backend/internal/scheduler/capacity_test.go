@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestServiceConfigNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ServiceConfig
+		want    ServiceConfig
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			cfg:  ServiceConfig{},
+			want: ServiceConfig{Rate: 1, Servers: 1, Schedule: []CapacityWindow{}},
+		},
+		{
+			name: "schedule sorted",
+			cfg:  ServiceConfig{Rate: 2, Servers: 3, Schedule: []CapacityWindow{{FromTick: 10, Rate: 4}, {FromTick: 5, Rate: 0}}},
+			want: ServiceConfig{Rate: 2, Servers: 3, Schedule: []CapacityWindow{{FromTick: 5, Rate: 0}, {FromTick: 10, Rate: 4}}},
+		},
+		{
+			name:    "negative servers",
+			cfg:     ServiceConfig{Servers: -1},
+			wantErr: "service rate and servers must be positive",
+		},
+		{
+			name:    "negative window",
+			cfg:     ServiceConfig{Schedule: []CapacityWindow{{FromTick: -1, Rate: 1}}},
+			wantErr: "from_tick >= 0 and rate >= 0",
+		},
+		{
+			// queue sẽ không bao giờ được phục vụ nữa
+			name:    "closed forever",
+			cfg:     ServiceConfig{Schedule: []CapacityWindow{{FromTick: 10, Rate: 0}, {FromTick: 5, Rate: 2}}},
+			wantErr: "last capacity window must have rate > 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.Normalize()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapacityAt(t *testing.T) {
+	cfg := ServiceConfig{Rate: 2, Servers: 3, Schedule: []CapacityWindow{{FromTick: 5, Rate: 0}, {FromTick: 10, Rate: 4}}}
+
+	for tick, want := range map[int]int{0: 6, 4: 6, 5: 0, 9: 0, 10: 12, 1000: 12} {
+		if got := cfg.capacityAt(tick); got != want {
+			t.Errorf("capacityAt(%d) = %d, want %d", tick, got, want)
+		}
+	}
+}
+
+func TestEngineCapacity(t *testing.T) {
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, ArrivalAt: 0},
+			{ID: 2, ArrivalAt: 0},
+			{ID: 3, ArrivalAt: 0},
+			{ID: 4, ArrivalAt: 1},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		service  ServiceConfig
+		requests []models.Request // nil = requests()
+		want     []string
+	}{
+		{
+			name:    "one per tick",
+			service: DefaultServiceConfig(),
+			want:    []string{"0 select 1", "1 select 2", "2 select 3", "3 select 4"},
+		},
+		{
+			name:    "rate 2",
+			service: ServiceConfig{Rate: 2, Servers: 1},
+			want:    []string{"0 select 1", "0 select 2", "1 select 3", "1 select 4"},
+		},
+		{
+			name:    "3 servers",
+			service: ServiceConfig{Rate: 1, Servers: 3},
+			want:    []string{"0 select 1", "0 select 2", "0 select 3", "1 select 4"},
+		},
+		{
+			// đóng tới tick 3 rồi mở rate 3
+			name:    "schedule",
+			service: ServiceConfig{Rate: 1, Servers: 1, Schedule: []CapacityWindow{{FromTick: 0, Rate: 0}, {FromTick: 3, Rate: 3}}},
+			want:    []string{"3 select 1", "3 select 2", "3 select 3", "4 select 4"},
+		},
+		{
+			// rate 3 -> 1 ở tick 1: slot 0 rảnh ở tick 2 nhưng 2 và 3 vẫn chạy
+			name:    "shrink while running",
+			service: ServiceConfig{Rate: 1, Servers: 1, Schedule: []CapacityWindow{{FromTick: 0, Rate: 3}, {FromTick: 1, Rate: 1}}},
+			requests: []models.Request{
+				{ID: 1, ArrivalAt: 0, ServiceTime: 2},
+				{ID: 2, ArrivalAt: 0, ServiceTime: 4},
+				{ID: 3, ArrivalAt: 0, ServiceTime: 4},
+				{ID: 4, ArrivalAt: 1},
+			},
+			want: []string{"0 select 1", "0 select 2", "0 select 3", "4 select 4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs := tt.requests
+			if reqs == nil {
+				reqs = requests()
+			}
+			w := testWorkload(reqs...)
+			w.Service = tt.service
+			assertTimeline(t, timeline(NewFIFOStrategy().Schedule(w), ActionSelect), tt.want)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"container/heap"
+	"math"
+)
+
+// DeadlineStrategy implements the real-time disciplines:
+//   - EDF (earliest deadline first) serves the smallest absolute Deadline
+//   - LLF (least laxity first) serves the smallest Deadline - remaining service
+//
+// Requests without a deadline come after every request that has one, in
+// arrival order.
+type DeadlineStrategy struct {
+	leastLaxity bool
+}
+
+func NewEDFStrategy() *DeadlineStrategy {
+	return &DeadlineStrategy{}
+}
+
+func NewLLFStrategy() *DeadlineStrategy {
+	return &DeadlineStrategy{leastLaxity: true}
+}
+
+func (s *DeadlineStrategy) Name() string {
+	if s.leastLaxity {
+		return "llf"
+	}
+	return "edf"
+}
+
+func (s *DeadlineStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, &deadlineQueue{
+		leastLaxity: s.leastLaxity,
+		ready:       &deadlineHeap{},
+		members:     newQueueMembership(),
+	})
+}
+
+type deadlineQueue struct {
+	leastLaxity bool
+	ready       *deadlineHeap
+	members     queueMembership
+}
+
+// key is the absolute tick the request is ordered by, +Inf without a deadline.
+// Laxity at tick now is key - now; now is the same for every queued request.
+func (q *deadlineQueue) key(req runtimeRequest) float64 {
+	if req.Deadline == 0 {
+		return math.Inf(1)
+	}
+	if q.leastLaxity {
+		return float64(req.Deadline - req.Remaining)
+	}
+	return float64(req.Deadline)
+}
+
+// score is the slack left at tick now, the lower the more urgent
+func (q *deadlineQueue) score(item deadlineItem, now int) float64 {
+	return item.key - float64(now)
+}
+
+func (q *deadlineQueue) Push(req runtimeRequest) {
+	heap.Push(q.ready, deadlineItem{runtimeRequest: req, key: q.key(req)})
+	q.members.add(req.ID)
+}
+
+func (q *deadlineQueue) Pop(now int) (runtimeRequest, float64) {
+	for {
+		item := heap.Pop(q.ready).(deadlineItem)
+		if q.members.skip(item.ID) {
+			continue
+		}
+		q.members.served(item.ID)
+		return item.runtimeRequest, q.score(item, now)
+	}
+}
+
+func (q *deadlineQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, item := range q.ready.items {
+		if q.members.queued[item.ID] {
+			fn(item.runtimeRequest, q.score(item, now))
+		}
+	}
+}
+
+func (q *deadlineQueue) Remove(id int) bool {
+	return q.members.remove(id)
+}
+
+func (q *deadlineQueue) Len() int {
+	return q.members.len()
+}
+
+func (q *deadlineQueue) lowerScoreFirst() bool { return true }
+
+type deadlineItem struct {
+	runtimeRequest
+	key float64
+}
+
+// deadlineHeap is a min-heap by key, then ArrivalAt, then ID
+type deadlineHeap struct {
+	items []deadlineItem
+}
+
+func (h *deadlineHeap) Len() int { return len(h.items) }
+
+func (h *deadlineHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if a.key != b.key {
+		return a.key < b.key
+	}
+	if a.ArrivalAt != b.ArrivalAt {
+		return a.ArrivalAt < b.ArrivalAt
+	}
+	return a.ID < b.ID
+}
+
+func (h *deadlineHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *deadlineHeap) Push(x any) { h.items = append(h.items, x.(deadlineItem)) }
+
+func (h *deadlineHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestDeadlineOrder(t *testing.T) {
+	// request 1 has the later deadline but the least laxity (10-8 < 5-1)
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Deadline: 10, ServiceTime: 8},
+			{ID: 2, Deadline: 5, ServiceTime: 1},
+			{ID: 3},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		want     []string
+	}{
+		{
+			name:     "edf",
+			strategy: NewEDFStrategy(),
+			want:     []string{"0 select 2", "1 select 1", "9 select 3"},
+		},
+		{
+			// request 2 cannot start after tick 5 and is dropped at 6
+			name:     "llf",
+			strategy: NewLLFStrategy(),
+			want:     []string{"0 select 1", "6 drop 2", "8 select 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions := tt.strategy.Schedule(testWorkload(requests()...))
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionDrop), tt.want)
+		})
+	}
+}
+
+func TestDeadlineDropsWhenTooLateToFinish(t *testing.T) {
+	// request 3 must start by tick 2 to be served in ticks 2-4, it is
+	// still queued at tick 3 and dropped instead of completing late
+	requests := []models.Request{
+		{ID: 1, Deadline: 10, ServiceTime: 3},
+		{ID: 2, Deadline: 3, ServiceTime: 2},
+		{ID: 3, Deadline: 4, ServiceTime: 3},
+		{ID: 4, Deadline: 3, ServiceTime: 2},
+	}
+	decisions := NewEDFStrategy().Schedule(testWorkload(requests...))
+	assertTimeline(t, timeline(decisions, ActionSelect, ActionDrop, ActionComplete), []string{
+		"0 select 2", "2 complete 2", "2 select 4", "3 drop 3",
+		"4 complete 4", "4 select 1", "7 complete 1",
+	})
+}
@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"container/heap"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// DRFStrategy is Dominant Resource Fairness: the next request comes from
+// the client with the lowest dominant share (its largest allocated fraction
+// of any resource) whose oldest request still fits the remaining capacity.
+// In a pool run every pool is a resource. Without a known capacity every
+// share stays 0 and clients are served by their oldest request.
+type DRFStrategy struct{}
+
+func NewDRFStrategy() *DRFStrategy {
+	return &DRFStrategy{}
+}
+
+func (s *DRFStrategy) Name() string {
+	return "drf"
+}
+
+func (s *DRFStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, newDRFQueue())
+}
+
+// DominantShare is max over resources of allocated/capacity
+func DominantShare(allocated, capacity map[string]int) float64 {
+	share := 0.0
+	for r, amount := range allocated {
+		if c := capacity[r]; c > 0 {
+			if s := float64(amount) / float64(c); s > share {
+				share = s
+			}
+		}
+	}
+	return share
+}
+
+type drfQueue struct {
+	buckets map[int]*drfBucket
+	clients drfHeap
+	members queueMembership
+	owner   map[int]int // request ID -> client ID
+	// inv là capacity còn lại do runQueue cập nhật, nil = không biết
+	inv inventory
+}
+
+func newDRFQueue() *drfQueue {
+	return &drfQueue{
+		buckets: map[int]*drfBucket{},
+		members: newQueueMembership(),
+		owner:   map[int]int{},
+	}
+}
+
+func (q *drfQueue) useInventory(inv inventory) {
+	q.inv = inv
+}
+
+func (q *drfQueue) fits(req models.Request) bool {
+	return q.inv == nil || q.inv.fits(req)
+}
+
+// exhausted reports whether every resource is used up, so nothing can fit
+func (q *drfQueue) exhausted() bool {
+	return q.inv != nil && q.inv.empty()
+}
+
+func (q *drfQueue) Push(req runtimeRequest) {
+	b, ok := q.buckets[req.ClientID]
+	if !ok {
+		b = &drfBucket{clientID: req.ClientID, allocated: map[string]int{}, index: -1}
+		q.buckets[req.ClientID] = b
+	}
+
+	b.requests = append(b.requests, req)
+	q.members.add(req.ID)
+	q.owner[req.ID] = req.ClientID
+
+	q.rekey(b)
+}
+
+func (q *drfQueue) Pop(now int) (runtimeRequest, float64) {
+	var skipped []*drfBucket
+	var b *drfBucket
+
+	for q.clients.Len() > 0 && !q.exhausted() {
+		top := heap.Pop(&q.clients).(*drfBucket)
+		if q.fits(top.requests[0].Request) {
+			b = top
+			break
+		}
+		skipped = append(skipped, top)
+	}
+	switch {
+	case b != nil:
+	case len(skipped) > 0:
+		// không client nào vừa, phục vụ client có share thấp nhất để bị reject
+		b = skipped[0]
+		skipped = skipped[1:]
+	default:
+		b = q.clients.items[0]
+	}
+	for _, s := range skipped {
+		heap.Push(&q.clients, s)
+	}
+
+	selected := b.requests[0]
+	b.requests = b.requests[1:]
+	q.members.served(selected.ID)
+	delete(q.owner, selected.ID)
+
+	q.rekey(b)
+
+	return selected, b.share
+}
+
+// charge adds what the inventory granted req to its client's share: the
+// demand vector in a resource run, the units of the pool otherwise
+func (q *drfQueue) charge(req runtimeRequest, _ int, pool string, units int) {
+	if q.inv == nil {
+		return
+	}
+	b := q.buckets[req.ClientID]
+	if pool == "" {
+		for r, d := range req.Resources {
+			b.allocated[r] += d
+		}
+	} else {
+		b.allocated[pool] += units
+	}
+	b.share = DominantShare(b.allocated, q.inv.capacity())
+
+	if b.index >= 0 {
+		heap.Fix(&q.clients, b.index)
+	}
+}
+
+func (q *drfQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, b := range q.clients.items {
+		for _, req := range b.requests {
+			if q.members.queued[req.ID] {
+				fn(req, b.share)
+			}
+		}
+	}
+}
+
+func (q *drfQueue) Remove(id int) bool {
+	if !q.members.remove(id) {
+		return false
+	}
+
+	clientID := q.owner[id]
+	delete(q.owner, id)
+	q.rekey(q.buckets[clientID])
+
+	return true
+}
+
+func (q *drfQueue) Len() int {
+	return q.members.len()
+}
+
+func (q *drfQueue) lowerScoreFirst() bool { return true }
+
+// rekey restores the client heap after bucket b changed.
+// Removed requests are discarded once they reach the head of their bucket.
+func (q *drfQueue) rekey(b *drfBucket) {
+	for len(b.requests) > 0 && q.members.skip(b.requests[0].ID) {
+		b.requests = b.requests[1:]
+	}
+
+	switch {
+	case len(b.requests) == 0 && b.index >= 0:
+		heap.Remove(&q.clients, b.index)
+	case len(b.requests) == 0:
+	case b.index < 0:
+		heap.Push(&q.clients, b)
+	default:
+		heap.Fix(&q.clients, b.index)
+	}
+}
+
+type drfBucket struct {
+	clientID  int
+	allocated map[string]int
+	share     float64          // dominant share of allocated
+	requests  []runtimeRequest // FIFO
+	index     int              // vị trí trong drfHeap, -1 nếu không có request nào
+}
+
+// drfHeap is an indexed min-heap of clients by dominant share,
+// ties broken by their oldest request
+type drfHeap struct {
+	items []*drfBucket
+}
+
+func (h *drfHeap) Len() int { return len(h.items) }
+
+func (h *drfHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if a.share != b.share {
+		return a.share < b.share
+	}
+	ra, rb := a.requests[0], b.requests[0]
+	if ra.EnqueueTick != rb.EnqueueTick {
+		return ra.EnqueueTick < rb.EnqueueTick
+	}
+	return ra.ID < rb.ID
+}
+
+func (h *drfHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *drfHeap) Push(x any) {
+	b := x.(*drfBucket)
+	b.index = len(h.items)
+	h.items = append(h.items, b)
+}
+
+func (h *drfHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	b := old[n-1]
+	old[n-1] = nil
+	b.index = -1
+	h.items = old[:n-1]
+	return b
+}
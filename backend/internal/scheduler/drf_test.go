@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestDRF(t *testing.T) {
+	// Ghodsi et al.: 9 CPU, 18 GB; A asks <1 CPU, 4 GB>, B <3 CPU, 1 GB>
+	resourceRun := func() Workload {
+		var requests []models.Request
+		for i := 1; i <= 10; i++ {
+			req := models.Request{ID: i, ClientID: 1, Size: 1, Resources: map[string]int{"cpu": 1, "mem": 4}}
+			if i > 5 {
+				req.ClientID = 2
+				req.Resources = map[string]int{"cpu": 3, "mem": 1}
+			}
+			requests = append(requests, req)
+		}
+		w := testWorkload(requests...)
+		w.Resources = map[string]int{"cpu": 9, "mem": 18}
+		return w
+	}
+	// each pool is a resource: one unit of the 2-unit pool "a" weighs
+	// as much as five units of pool "b"
+	poolRun := func() Workload {
+		var requests []models.Request
+		for i := 1; i <= 6; i++ {
+			req := models.Request{ID: i, ClientID: 1, Size: 1, Pools: []string{"a"}}
+			if i > 3 {
+				req.ClientID = 2
+				req.Pools = []string{"b"}
+			}
+			requests = append(requests, req)
+		}
+		w := testWorkload(requests...)
+		w.Pools = map[string]int{"a": 2, "b": 10}
+		return w
+	}
+
+	tests := []struct {
+		name string
+		w    Workload
+		want []string
+	}{
+		{
+			// A gets 3 tasks and B 2, CPU is gone and the rest is rejected
+			name: "resources",
+			w:    resourceRun(),
+			want: []string{
+				"0 select 1", "1 select 6", "2 select 2", "3 select 7", "4 select 3",
+				"5 reject 4", "5 reject 5", "5 reject 8", "5 reject 9", "5 reject 10",
+			},
+		},
+		{
+			name: "pools",
+			w:    poolRun(),
+			want: []string{"0 select 1", "1 select 4", "2 select 5", "3 select 6", "4 select 2", "5 reject 3"},
+		},
+		{
+			// capacity unknown: shares stay 0, oldest request first
+			name: "no capacity",
+			w: testWorkload(
+				models.Request{ID: 1, ClientID: 1, ArrivalAt: 0},
+				models.Request{ID: 2, ClientID: 1, ArrivalAt: 0},
+				models.Request{ID: 3, ClientID: 2, ArrivalAt: 1},
+			),
+			want: []string{"0 select 1", "1 select 2", "2 select 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions := NewDRFStrategy().Schedule(tt.w)
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionReject), tt.want)
+		})
+	}
+}
+
+func TestDominantShare(t *testing.T) {
+	capacity := map[string]int{"cpu": 10, "gpu": 4}
+	tests := []struct {
+		name      string
+		allocated map[string]int
+		want      float64
+	}{
+		{"nothing", map[string]int{}, 0},
+		{"cpu dominant", map[string]int{"cpu": 5, "gpu": 1}, 0.5},
+		{"gpu dominant", map[string]int{"cpu": 1, "gpu": 3}, 0.75},
+		{"unknown resource ignored", map[string]int{"ram": 100}, 0},
+	}
+	for _, tt := range tests {
+		if got := DominantShare(tt.allocated, capacity); got != tt.want {
+			t.Errorf("%s: share = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,529 @@
+package scheduler
+
+import (
+	"container/heap"
+	"math/rand"
+	"sort"
+)
+
+// eventKind also orders events that fall on the same time:
+// arrivals first, then timeouts, completions and capacity changes
+type eventKind int
+
+const (
+	eventArrival    eventKind = iota // request joins the queue
+	eventTimeout                     // request can no longer finish by its Deadline
+	eventRenege                      // client ran out of Patience
+	eventCompletion                  // running request finished its service
+	eventCapacity                    // a capacity window starts
+	eventSnapshot                    // wait snapshot of the queued requests
+)
+
+// calendarEvent is one entry of the event calendar
+type calendarEvent struct {
+	At   float64 // simulation time, ticks are whole numbers
+	Kind eventKind
+	Key  int // orders events of one kind at the same time: request ID or slot
+	seq  int
+
+	req runtimeRequest // arrival, timeout
+	job *runningJob    // completion
+}
+
+// eventCalendar is the future event list of a discrete-event simulation.
+// Time is continuous, events at the same time come out by Kind, Key then
+// scheduling order so a run is deterministic.
+type eventCalendar struct {
+	items []calendarEvent
+	seq   int
+}
+
+func (c *eventCalendar) Len() int { return len(c.items) }
+
+func (c *eventCalendar) Less(i, j int) bool {
+	a, b := c.items[i], c.items[j]
+	if a.At != b.At {
+		return a.At < b.At
+	}
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Key != b.Key {
+		return a.Key < b.Key
+	}
+	return a.seq < b.seq
+}
+
+func (c *eventCalendar) Swap(i, j int) { c.items[i], c.items[j] = c.items[j], c.items[i] }
+
+func (c *eventCalendar) Push(x any) { c.items = append(c.items, x.(calendarEvent)) }
+
+func (c *eventCalendar) Pop() any {
+	old := c.items
+	n := len(old)
+	item := old[n-1]
+	c.items = old[:n-1]
+	return item
+}
+
+// schedule adds ev to the calendar
+func (c *eventCalendar) schedule(ev calendarEvent) {
+	ev.seq = c.seq
+	c.seq++
+	heap.Push(c, ev)
+}
+
+// next removes and returns the earliest event
+func (c *eventCalendar) next() calendarEvent {
+	return heap.Pop(c).(calendarEvent)
+}
+
+// peekTime is the time of the earliest event, the calendar must not be empty
+func (c *eventCalendar) peekTime() float64 {
+	return c.items[0].At
+}
+
+// runQueue là engine discrete-event dùng chung cho mọi strategy,
+// strategy chỉ quyết định "pick next" qua readyQueue
+//   - request vào queue khi tới ArrivalAt
+//   - request không còn kịp xong trước Deadline bị drop trước khi phục vụ
+//   - mỗi tick có Service.capacityAt(tick) server slot, request giữ slot
+//     trong ServiceTime tick rồi complete
+//   - queue preemptive (SRPT) có thể lấy slot của request đang chạy
+//   - mỗi WaitSnapshotEvery tick ghi lại score của các request còn chờ
+//   - nếu Workload khai báo capacity (Pools/Resources), request không còn
+//     được phục vụ bị reject ngay thay vì select; khi hết sạch capacity thì
+//     mọi request còn chờ và request tới sau đều bị reject một lần
+//   - Admission giới hạn độ dài queue, request bị loại ghi ActionShed
+//   - request hết Patience thì renege; request bị reject/shed/drop được
+//     client retry theo Workload.Retry thành request mới (RetryOf, Attempt)
+//
+// Giữa hai event không có gì thay đổi nên engine nhảy thẳng tới event kế
+// tiếp thay vì tick++ qua các khoảng rảnh.
+func runQueue(w Workload, q readyQueue) []Decision {
+	e := &queueEngine{
+		w:            w,
+		q:            q,
+		running:      map[int]*runningJob{},
+		started:      map[int]bool{},
+		nextSnapshot: -1,
+		inv:          newInventory(w),
+	}
+	e.preempting, _ = q.(preemptingQueue)
+	e.charging, _ = q.(chargingQueue)
+	if aware, ok := q.(capacityAwareQueue); ok && e.inv != nil {
+		aware.useInventory(e.inv)
+	}
+	if w.Admission.Policy == AdmitEarlyDrop {
+		// seed+2 lottery, seed+3/+4 size và service time của generator
+		e.rng = rand.New(rand.NewSource(w.Seed + 5))
+	}
+	// seed+6 là patience của generator
+	e.retryRng = rand.New(rand.NewSource(w.Seed + 7))
+
+	for _, req := range sortByArrival(w.Requests) {
+		e.nextID = max(e.nextID, req.ID+1)
+		e.calendar.schedule(calendarEvent{
+			At:   float64(max(req.ArrivalAt, 0)),
+			Kind: eventArrival,
+			Key:  req.ID,
+			req:  runtimeRequest{Request: req, Remaining: serviceTime(req)},
+		})
+	}
+	for _, window := range w.Service.Schedule {
+		e.calendar.schedule(calendarEvent{At: float64(window.FromTick), Kind: eventCapacity})
+	}
+
+	for e.calendar.Len() > 0 {
+		now := e.calendar.peekTime()
+		tick := int(now)
+		for e.calendar.Len() > 0 && e.calendar.peekTime() == now {
+			e.handle(e.calendar.next(), tick)
+		}
+		e.dispatch(tick)
+	}
+
+	return e.decisions
+}
+
+// queueEngine is the state of one runQueue run
+type queueEngine struct {
+	w          Workload
+	q          readyQueue
+	preempting preemptingQueue // nil if q never preempts
+	charging   chargingQueue   // nil if q keeps no allocation state
+	inv        inventory       // capacity left, nil = unknown
+	rng        *rand.Rand      // early_drop only
+	retryRng   *rand.Rand      // retry jitter
+	nextID     int             // ID of the next retry request
+	calendar   eventCalendar
+	decisions  []Decision
+
+	running      map[int]*runningJob // slot -> job
+	started      map[int]bool        // request ID -> đã được chọn ít nhất 1 lần
+	nextSnapshot int                 // tick of the scheduled snapshot event, -1 = none
+}
+
+func (e *queueEngine) handle(ev calendarEvent, tick int) {
+	switch ev.Kind {
+	case eventArrival:
+		req := ev.req
+		req.EnqueueTick = tick
+		if e.soldOut(req) {
+			e.reject(tick, req, 0)
+			return
+		}
+		admitted, score, evicted := e.admit(tick, req)
+		if !admitted {
+			e.shed(tick, req, score)
+			return
+		}
+		if req.Deadline > 0 {
+			// bắt đầu muộn nhất ở Deadline-Remaining+1 để xong trong tick Deadline
+			e.calendar.schedule(calendarEvent{
+				At:   float64(max(req.Deadline-req.Remaining+2, tick)),
+				Kind: eventTimeout,
+				Key:  req.ID,
+				req:  req,
+			})
+		}
+
+		e.decisions = append(e.decisions, Decision{
+			Tick:    tick,
+			Request: req.Request,
+			Action:  ActionEnqueue,
+		})
+		if req.Patience > 0 {
+			e.calendar.schedule(calendarEvent{
+				At:   float64(tick + req.Patience),
+				Kind: eventRenege,
+				Key:  req.ID,
+				req:  req,
+			})
+		}
+		if evicted != nil {
+			e.shed(tick, evicted.req, evicted.score)
+		}
+
+	case eventTimeout:
+		if e.started[ev.req.ID] || !e.q.Remove(ev.req.ID) {
+			return // đã được phục vụ
+		}
+
+		e.decisions = append(e.decisions, Decision{
+			Tick:    tick,
+			Request: ev.req.Request,
+			Action:  ActionDrop,
+		})
+		e.retry(tick, ev.req)
+
+	case eventRenege:
+		if e.started[ev.req.ID] || !e.q.Remove(ev.req.ID) {
+			return // đã được phục vụ hoặc đã rời queue
+		}
+
+		e.decisions = append(e.decisions, Decision{
+			Tick:    tick,
+			Request: ev.req.Request,
+			Action:  ActionRenege,
+		})
+
+	case eventCompletion:
+		slot := ev.Key
+		if e.running[slot] != ev.job {
+			return // job bị preempt, completion này đã cũ
+		}
+		delete(e.running, slot)
+
+		e.decisions = append(e.decisions, Decision{
+			Tick:    tick,
+			Request: ev.job.req.Request,
+			Server:  slot % e.w.Service.Servers,
+			Action:  ActionComplete,
+		})
+
+	case eventCapacity, eventSnapshot:
+		// dispatch đọc capacity và snapshot theo tick hiện tại
+	}
+}
+
+// dispatch fills the free slots, preempts if the queue allows it and
+// takes the wait snapshot, after all events of tick were handled
+func (e *queueEngine) dispatch(tick int) {
+	if e.q.Len() == 0 {
+		return
+	}
+
+	// capacity giảm thì job đang chạy ở slot >= capacity vẫn giữ chỗ tới khi
+	// xong, nên chỉ start khi số job chạy < capacity; khi đó luôn có slot
+	// rảnh trong [0, capacity)
+	capacity := e.w.Service.capacityAt(tick)
+	for slot := 0; slot < capacity && len(e.running) < capacity && e.q.Len() > 0; slot++ {
+		if e.running[slot] != nil {
+			continue
+		}
+		selected, score, ok := e.popServable(tick)
+		if !ok {
+			break
+		}
+		e.start(tick, slot, selected, score)
+	}
+
+	for e.preempting != nil && e.q.Len() > 0 {
+		head, _ := e.preempting.Peek()
+		slot, victim := e.victim(tick)
+		if victim == nil || !e.preempting.Preempts(head, victim.remainingAt(tick)) {
+			break
+		}
+		delete(e.running, slot)
+
+		preempted := victim.remainingAt(tick)
+		e.q.Push(preempted)
+		e.decisions = append(e.decisions, Decision{
+			Tick:    tick,
+			Request: preempted.Request,
+			Server:  slot % e.w.Service.Servers,
+			Action:  ActionPreempt,
+		})
+
+		selected, score, ok := e.popServable(tick)
+		if !ok {
+			break
+		}
+		e.start(tick, slot, selected, score)
+	}
+
+	every := e.w.WaitSnapshotEvery
+	if every <= 0 {
+		return
+	}
+	if tick%every == 0 {
+		e.q.Scores(tick, func(req runtimeRequest, score float64) {
+			e.decisions = append(e.decisions, Decision{
+				Tick:    tick,
+				Request: req.Request,
+				Score:   score,
+				Action:  ActionWait,
+			})
+		})
+	}
+	// request còn chờ thì cần snapshot kế tiếp dù không có event nào khác
+	if e.q.Len() > 0 && e.nextSnapshot <= tick {
+		e.nextSnapshot = (tick/every + 1) * every
+		e.calendar.schedule(calendarEvent{At: float64(e.nextSnapshot), Kind: eventSnapshot})
+	}
+}
+
+// victim is the running job the queue ranks last: a job replaces the
+// current pick unless it would preempt it, so ties go to the highest slot
+func (e *queueEngine) victim(now int) (int, *runningJob) {
+	bestSlot, best := -1, (*runningJob)(nil)
+	for _, slot := range sortedSlots(e.running) {
+		job := e.running[slot]
+		if best == nil || !e.preempting.Preempts(job.remainingAt(now), best.remainingAt(now)) {
+			bestSlot, best = slot, job
+		}
+	}
+	return bestSlot, best
+}
+
+// start gives slot to req and schedules its completion
+func (e *queueEngine) start(tick, slot int, req runtimeRequest, score float64) {
+	action := ActionSelect
+	if e.started[req.ID] {
+		action = ActionResume
+	} else {
+		pool, units := "", 0
+		if e.inv != nil {
+			pool, units = e.inv.take(req.Request, e.w.Fill)
+		}
+		if e.charging != nil {
+			e.charging.charge(req, tick, pool, units)
+		}
+	}
+	e.started[req.ID] = true
+
+	job := &runningJob{req: req, startTick: tick}
+	e.running[slot] = job
+	e.calendar.schedule(calendarEvent{
+		At:   float64(job.finish()),
+		Kind: eventCompletion,
+		Key:  slot,
+		job:  job,
+	})
+
+	e.decisions = append(e.decisions, Decision{
+		Tick:    tick,
+		Request: req.Request,
+		Score:   score,
+		Server:  slot % e.w.Service.Servers,
+		Action:  action,
+	})
+
+	if e.inv != nil && e.inv.empty() {
+		e.closeOut(tick)
+	}
+}
+
+// popServable pops until the queue returns a request the capacity left can
+// serve, rejecting the others. False if the queue ran empty.
+func (e *queueEngine) popServable(tick int) (runtimeRequest, float64, bool) {
+	for e.q.Len() > 0 {
+		req, score := e.q.Pop(tick)
+		if e.started[req.ID] || e.inv == nil || e.inv.canTake(req.Request, e.w.Fill) {
+			return req, score, true
+		}
+		e.reject(tick, req, score)
+	}
+	return runtimeRequest{}, 0, false
+}
+
+// soldOut reports whether the capacity is gone and req cannot be served
+func (e *queueEngine) soldOut(req runtimeRequest) bool {
+	return e.inv != nil && e.inv.empty() && !e.inv.canTake(req.Request, e.w.Fill)
+}
+
+// closeOut rejects every queued request once the capacity is gone, in ID
+// order. Preempted requests keep their allocation and stay queued.
+func (e *queueEngine) closeOut(tick int) {
+	type queued struct {
+		req   runtimeRequest
+		score float64
+	}
+	var out []queued
+	e.q.Scores(tick, func(req runtimeRequest, score float64) {
+		if !e.started[req.ID] && e.soldOut(req) {
+			out = append(out, queued{req, score})
+		}
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].req.ID < out[j].req.ID })
+
+	for _, o := range out {
+		if e.q.Remove(o.req.ID) {
+			e.reject(tick, o.req, o.score)
+		}
+	}
+}
+
+func (e *queueEngine) reject(tick int, req runtimeRequest, score float64) {
+	e.decisions = append(e.decisions, Decision{
+		Tick:    tick,
+		Request: req.Request,
+		Score:   score,
+		Action:  ActionReject,
+	})
+	e.retry(tick, req)
+}
+
+type scoredRequest struct {
+	req   runtimeRequest
+	score float64
+}
+
+// admit applies w.Admission to req arriving at tick. It pushes req unless
+// req itself is shed (with its score), and returns the queued request
+// drop_lowest shed in its place.
+func (e *queueEngine) admit(tick int, req runtimeRequest) (bool, float64, *scoredRequest) {
+	cfg := e.w.Admission
+	full := cfg.MaxQueue > 0 && e.q.Len() >= cfg.MaxQueue
+
+	switch cfg.Policy {
+	case AdmitEarlyDrop:
+		if full {
+			return false, 0, nil
+		}
+		p := cfg.earlyDropChance(e.q.Len(), e.w.Clients[req.ClientID].Class)
+		if p > 0 && e.rng.Float64() < p {
+			return false, 0, nil
+		}
+
+	case AdmitDropLowest:
+		if !full {
+			break
+		}
+		e.q.Push(req)
+		worst := e.lowestScored(tick)
+		e.q.Remove(worst.req.ID)
+		if worst.req.ID == req.ID {
+			return false, worst.score, nil
+		}
+		return true, 0, &worst
+
+	default: // tail drop
+		if full {
+			return false, 0, nil
+		}
+	}
+
+	e.q.Push(req)
+	return true, 0, nil
+}
+
+// lowestScored is the queued request least likely to be served next, ties
+// go to the latest arrival. Preempted requests keep their place.
+func (e *queueEngine) lowestScored(tick int) scoredRequest {
+	order, _ := e.q.(lowerScoreFirstQueue)
+	lowerFirst := order != nil && order.lowerScoreFirst()
+
+	var worst *scoredRequest
+	e.q.Scores(tick, func(req runtimeRequest, score float64) {
+		if e.started[req.ID] {
+			return
+		}
+		if worst == nil {
+			worst = &scoredRequest{req, score}
+			return
+		}
+		worse := score < worst.score
+		if lowerFirst {
+			worse = score > worst.score
+		}
+		if score == worst.score {
+			worse = req.EnqueueTick > worst.req.EnqueueTick ||
+				req.EnqueueTick == worst.req.EnqueueTick && req.ID > worst.req.ID
+		}
+		if worse {
+			worst = &scoredRequest{req, score}
+		}
+	})
+	return *worst
+}
+
+func (e *queueEngine) shed(tick int, req runtimeRequest, score float64) {
+	e.decisions = append(e.decisions, Decision{
+		Tick:    tick,
+		Request: req.Request,
+		Score:   score,
+		Action:  ActionShed,
+	})
+	e.retry(tick, req)
+}
+
+// retry schedules the next attempt of failed as a new request linked to
+// the original, if the class policy allows one more
+func (e *queueEngine) retry(tick int, failed runtimeRequest) {
+	policy, ok := e.w.Retry[e.w.Clients[failed.ClientID].Class]
+	attempt := max(failed.Attempt, 1)
+	if !ok || attempt >= policy.MaxAttempts {
+		return
+	}
+
+	req := failed.Request
+	req.ID = e.nextID
+	e.nextID++
+	req.Attempt = attempt + 1
+	if req.RetryOf == 0 {
+		req.RetryOf = failed.ID
+	}
+	req.ArrivalAt = tick + policy.delay(attempt, e.retryRng)
+	if failed.Deadline > 0 {
+		req.Deadline = req.ArrivalAt + failed.Deadline - failed.ArrivalAt
+	}
+
+	e.calendar.schedule(calendarEvent{
+		At:   float64(req.ArrivalAt),
+		Kind: eventArrival,
+		Key:  req.ID,
+		req:  runtimeRequest{Request: req, Remaining: serviceTime(req)},
+	})
+}
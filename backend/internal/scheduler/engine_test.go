@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// timeline renders the decisions with one of actions as "tick action id"
+func timeline(decisions []Decision, actions ...string) []string {
+	keep := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		keep[a] = true
+	}
+	var out []string
+	for _, d := range decisions {
+		if keep[d.Action] {
+			out = append(out, fmt.Sprintf("%d %s %d", d.Tick, d.Action, d.Request.ID))
+		}
+	}
+	return out
+}
+
+// testWorkload gives every request its own client of weight 1
+func testWorkload(requests ...models.Request) Workload {
+	clients := make([]models.Client, 0, len(requests))
+	seen := map[int]bool{}
+	for i := range requests {
+		if requests[i].ClientID == 0 {
+			requests[i].ClientID = requests[i].ID
+		}
+		if requests[i].ServiceTime == 0 {
+			requests[i].ServiceTime = 1
+		}
+		if !seen[requests[i].ClientID] {
+			seen[requests[i].ClientID] = true
+			clients = append(clients, models.Client{ID: requests[i].ClientID, Class: "free", Weight: 1})
+		}
+	}
+	return NewWorkload(clients, requests, 1)
+}
+
+func assertTimeline(t *testing.T, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timeline\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestChargeOnlyAllocated(t *testing.T) {
+	// request 1 cannot fit the 3 units left and is rejected at tick 0:
+	// its client must not pay debt for it, so request 2 still wins the tie
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, ClientID: 1, Priority: 1, Size: 5},
+			{ID: 2, ClientID: 1, Priority: 1, Size: 1},
+			{ID: 3, ClientID: 2, Priority: 1, Size: 1},
+		}
+	}
+	expr, err := CompileExpr("priority*10 + wait - debt*2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+	}{
+		{"hybrid", NewHybridStrategy(defaultHybrid)},
+		{"expr", NewExprStrategy(expr)},
+	}
+	want := []string{"0 reject 1", "0 select 2", "1 select 3"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			w.Pools = map[string]int{DefaultPool: 3}
+			assertTimeline(t, timeline(tt.strategy.Schedule(w), ActionSelect, ActionReject), want)
+		})
+	}
+}
+
+func TestEngineServers(t *testing.T) {
+	// 2 servers, rate 1: a request holds its server for its service time
+	w := testWorkload(
+		models.Request{ID: 1, ServiceTime: 3},
+		models.Request{ID: 2, ServiceTime: 1},
+		models.Request{ID: 3, ServiceTime: 1},
+		models.Request{ID: 4, ServiceTime: 1},
+	)
+	w.Service = ServiceConfig{Rate: 1, Servers: 2}
+
+	decisions := NewFIFOStrategy().Schedule(w)
+	assertTimeline(t, timeline(decisions, ActionSelect, ActionComplete), []string{
+		"0 select 1", "0 select 2", "1 complete 2", "1 select 3",
+		"2 complete 3", "2 select 4", "3 complete 1", "3 complete 4",
+	})
+
+	servers := map[int]int{}
+	for _, d := range decisions {
+		if d.Action == ActionSelect {
+			servers[d.Request.ID] = d.Server
+		}
+	}
+	if want := map[int]int{1: 0, 2: 1, 3: 1, 4: 1}; !reflect.DeepEqual(servers, want) {
+		t.Errorf("servers = %v, want %v", servers, want)
+	}
+}
+
+func TestEngineWaitSnapshots(t *testing.T) {
+	w := testWorkload(
+		models.Request{ID: 1, ServiceTime: 2},
+		models.Request{ID: 2, ServiceTime: 2},
+		models.Request{ID: 3, ServiceTime: 2},
+		// sau khoảng rảnh dài queue trống, không có snapshot nào
+		models.Request{ID: 4, ArrivalAt: 1000},
+	)
+	w.WaitSnapshotEvery = 2
+
+	assertTimeline(t, timeline(NewFIFOStrategy().Schedule(w), ActionSelect, ActionWait), []string{
+		"0 select 1", "0 wait 2", "0 wait 3", "2 select 2", "2 wait 3",
+		"4 select 3", "1000 select 4",
+	})
+}
@@ -0,0 +1,482 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxExprLength bounds the source of a scoring expression
+const MaxExprLength = 512
+
+// maxExprDepth bounds the nesting of a scoring expression
+const maxExprDepth = 32
+
+// ExprEnv is what a scoring expression can read about one queued request
+type ExprEnv struct {
+	Priority float64
+	Wait     float64 // ticks since enqueue
+	Debt     float64 // allocations the client already got
+	Weight   float64 // Client.Weight
+	Size     float64 // units requested
+	Class    string
+}
+
+// Expr is a compiled scoring expression, safe to evaluate concurrently.
+//
+// Grammar (usual precedence, ^ is right associative and binds tighter than unary -):
+//
+//	expr    = compare
+//	compare = sum [ ("==" | "!=" | "<" | "<=" | ">" | ">=") sum ]
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | power
+//	power   = atom [ "^" unary ]
+//	atom    = number | variable | string | func "(" expr { "," expr } ")" | "(" expr ")"
+//
+// Variables: priority, wait, debt, weight, size and class. class is a string
+// and can only be compared (== / !=) with a "quoted" class name. Comparisons
+// yield 1 or 0. Functions: min, max, abs, sqrt, log, exp.
+// Division by zero and undefined results (e.g. (-1)^0.5) evaluate to 0.
+type Expr struct {
+	source string
+	eval   func(env *ExprEnv) float64
+}
+
+// CompileExpr parses and type-checks source once
+func CompileExpr(source string) (*Expr, error) {
+	if len(source) > MaxExprLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", MaxExprLength)
+	}
+	tokens, err := lexExpr(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	n, err := p.parseCompare(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+	if n.str != nil {
+		return nil, fmt.Errorf("expression must be a number, not a string")
+	}
+
+	return &Expr{source: source, eval: n.num}, nil
+}
+
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval scores one request
+func (e *Expr) Eval(env *ExprEnv) float64 {
+	v := e.eval(env)
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokString
+	tokOp
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func lexExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			v, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", src[start:i], start)
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: src[start:i], num: v, pos: start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: src[start:i], pos: start})
+
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], src[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: src[i+1 : i+1+end], pos: i})
+			i += end + 2
+
+		default:
+			op := src[i : i+1]
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "==", "!=", "<=", ">=":
+					op = two
+				}
+			}
+			switch op {
+			case "+", "-", "*", "/", "^", "(", ")", ",", "<", ">", "==", "!=", "<=", ">=":
+			default:
+				return nil, fmt.Errorf("unexpected %q at %d", op, i)
+			}
+			tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+// exprNode is a compiled sub-expression, exactly one of num/str is set
+type exprNode struct {
+	num func(env *ExprEnv) float64
+	str func(env *ExprEnv) string
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.pos] }
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokOp && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) number(n exprNode, tok exprToken) (func(env *ExprEnv) float64, error) {
+	if n.num == nil {
+		return nil, fmt.Errorf("%q at %d needs numbers, class can only be compared with == or !=", tok.text, tok.pos)
+	}
+	return n.num, nil
+}
+
+func (p *exprParser) parseCompare(depth int) (exprNode, error) {
+	if depth > maxExprDepth {
+		return exprNode{}, fmt.Errorf("expression is nested deeper than %d", maxExprDepth)
+	}
+	left, err := p.parseSum(depth)
+	if err != nil {
+		return exprNode{}, err
+	}
+
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return left, nil
+	}
+	switch tok.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseSum(depth)
+	if err != nil {
+		return exprNode{}, err
+	}
+
+	if left.str != nil || right.str != nil {
+		if left.str == nil || right.str == nil || (tok.text != "==" && tok.text != "!=") {
+			return exprNode{}, fmt.Errorf("%q at %d: class can only be compared with == or != to a string", tok.text, tok.pos)
+		}
+		l, r, eq := left.str, right.str, tok.text == "=="
+		return exprNode{num: func(env *ExprEnv) float64 { return boolToFloat((l(env) == r(env)) == eq) }}, nil
+	}
+
+	l, r := left.num, right.num
+	var cmp func(a, b float64) bool
+	switch tok.text {
+	case "==":
+		cmp = func(a, b float64) bool { return a == b }
+	case "!=":
+		cmp = func(a, b float64) bool { return a != b }
+	case "<":
+		cmp = func(a, b float64) bool { return a < b }
+	case "<=":
+		cmp = func(a, b float64) bool { return a <= b }
+	case ">":
+		cmp = func(a, b float64) bool { return a > b }
+	case ">=":
+		cmp = func(a, b float64) bool { return a >= b }
+	}
+	return exprNode{num: func(env *ExprEnv) float64 { return boolToFloat(cmp(l(env), r(env))) }}, nil
+}
+
+func (p *exprParser) parseSum(depth int) (exprNode, error) {
+	left, err := p.parseProduct(depth)
+	if err != nil {
+		return exprNode{}, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseProduct(depth)
+		if err != nil {
+			return exprNode{}, err
+		}
+		l, err := p.number(left, tok)
+		if err != nil {
+			return exprNode{}, err
+		}
+		r, err := p.number(right, tok)
+		if err != nil {
+			return exprNode{}, err
+		}
+		if tok.text == "+" {
+			left = exprNode{num: func(env *ExprEnv) float64 { return l(env) + r(env) }}
+		} else {
+			left = exprNode{num: func(env *ExprEnv) float64 { return l(env) - r(env) }}
+		}
+	}
+}
+
+func (p *exprParser) parseProduct(depth int) (exprNode, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return exprNode{}, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || (tok.text != "*" && tok.text != "/") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return exprNode{}, err
+		}
+		l, err := p.number(left, tok)
+		if err != nil {
+			return exprNode{}, err
+		}
+		r, err := p.number(right, tok)
+		if err != nil {
+			return exprNode{}, err
+		}
+		if tok.text == "*" {
+			left = exprNode{num: func(env *ExprEnv) float64 { return l(env) * r(env) }}
+		} else {
+			left = exprNode{num: func(env *ExprEnv) float64 {
+				d := r(env)
+				if d == 0 {
+					return 0
+				}
+				return l(env) / d
+			}}
+		}
+	}
+}
+
+func (p *exprParser) parseUnary(depth int) (exprNode, error) {
+	tok := p.peek()
+	if tok.kind == tokOp && tok.text == "-" {
+		p.next()
+		if depth+1 > maxExprDepth {
+			return exprNode{}, fmt.Errorf("expression is nested deeper than %d", maxExprDepth)
+		}
+		n, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return exprNode{}, err
+		}
+		v, err := p.number(n, tok)
+		if err != nil {
+			return exprNode{}, err
+		}
+		return exprNode{num: func(env *ExprEnv) float64 { return -v(env) }}, nil
+	}
+	return p.parsePower(depth)
+}
+
+func (p *exprParser) parsePower(depth int) (exprNode, error) {
+	base, err := p.parseAtom(depth)
+	if err != nil {
+		return exprNode{}, err
+	}
+	tok := p.peek()
+	if !p.accept("^") {
+		return base, nil
+	}
+	if depth+1 > maxExprDepth {
+		return exprNode{}, fmt.Errorf("expression is nested deeper than %d", maxExprDepth)
+	}
+	exp, err := p.parseUnary(depth + 1)
+	if err != nil {
+		return exprNode{}, err
+	}
+	b, err := p.number(base, tok)
+	if err != nil {
+		return exprNode{}, err
+	}
+	e, err := p.number(exp, tok)
+	if err != nil {
+		return exprNode{}, err
+	}
+	return exprNode{num: func(env *ExprEnv) float64 { return finite(math.Pow(b(env), e(env))) }}, nil
+}
+
+func (p *exprParser) parseAtom(depth int) (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		v := tok.num
+		return exprNode{num: func(*ExprEnv) float64 { return v }}, nil
+
+	case tokString:
+		s := tok.text
+		return exprNode{str: func(*ExprEnv) string { return s }}, nil
+
+	case tokIdent:
+		if p.peek().kind == tokOp && p.peek().text == "(" {
+			return p.parseCall(tok, depth)
+		}
+		switch tok.text {
+		case "priority":
+			return exprNode{num: func(env *ExprEnv) float64 { return env.Priority }}, nil
+		case "wait":
+			return exprNode{num: func(env *ExprEnv) float64 { return env.Wait }}, nil
+		case "debt":
+			return exprNode{num: func(env *ExprEnv) float64 { return env.Debt }}, nil
+		case "weight":
+			return exprNode{num: func(env *ExprEnv) float64 { return env.Weight }}, nil
+		case "size":
+			return exprNode{num: func(env *ExprEnv) float64 { return env.Size }}, nil
+		case "class":
+			return exprNode{str: func(env *ExprEnv) string { return env.Class }}, nil
+		}
+		return exprNode{}, fmt.Errorf("unknown variable %q at %d", tok.text, tok.pos)
+
+	case tokOp:
+		if tok.text == "(" {
+			n, err := p.parseCompare(depth + 1)
+			if err != nil {
+				return exprNode{}, err
+			}
+			if !p.accept(")") {
+				return exprNode{}, fmt.Errorf("missing ) for ( at %d", tok.pos)
+			}
+			return n, nil
+		}
+	}
+	return exprNode{}, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+}
+
+// exprFuncs maps function name -> arity (-1 = at least 1) and implementation
+var exprFuncs = map[string]struct {
+	arity int
+	fn    func(args []float64) float64
+}{
+	"min": {-1, func(a []float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	}},
+	"max": {-1, func(a []float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	}},
+	"abs":  {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt": {1, func(a []float64) float64 { return finite(math.Sqrt(a[0])) }},
+	"log":  {1, func(a []float64) float64 { return finite(math.Log(a[0])) }},
+	"exp":  {1, func(a []float64) float64 { return finite(math.Exp(a[0])) }},
+}
+
+func (p *exprParser) parseCall(name exprToken, depth int) (exprNode, error) {
+	f, ok := exprFuncs[name.text]
+	if !ok {
+		return exprNode{}, fmt.Errorf("unknown function %q at %d", name.text, name.pos)
+	}
+	p.next() // (
+
+	var args []func(env *ExprEnv) float64
+	for {
+		n, err := p.parseCompare(depth + 1)
+		if err != nil {
+			return exprNode{}, err
+		}
+		v, err := p.number(n, name)
+		if err != nil {
+			return exprNode{}, err
+		}
+		args = append(args, v)
+		if p.accept(")") {
+			break
+		}
+		if !p.accept(",") {
+			return exprNode{}, fmt.Errorf("expected , or ) at %d", p.peek().pos)
+		}
+	}
+	if f.arity >= 0 && len(args) != f.arity {
+		return exprNode{}, fmt.Errorf("%s takes %d argument(s), got %d", name.text, f.arity, len(args))
+	}
+
+	fn := f.fn
+	return exprNode{num: func(env *ExprEnv) float64 {
+		values := make([]float64, len(args))
+		for i, a := range args {
+			values[i] = a(env)
+		}
+		return fn(values)
+	}}, nil
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// finite maps undefined results (NaN) to 0, infinities are kept for ordering
+func finite(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
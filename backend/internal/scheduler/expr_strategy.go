@@ -0,0 +1,101 @@
+package scheduler
+
+import "github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+
+// ExprStrategy serves the queued request with the highest score of a
+// user-defined expression (see Expr). The expression may be non-linear in
+// wait, so unlike hybrid every queued request is re-scored on each pop.
+type ExprStrategy struct {
+	expr *Expr
+}
+
+func NewExprStrategy(expr *Expr) *ExprStrategy {
+	return &ExprStrategy{expr: expr}
+}
+
+func (s *ExprStrategy) Name() string {
+	return "expr"
+}
+
+func (s *ExprStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, &exprQueue{
+		expr:    s.expr,
+		clients: w.Clients,
+		debt:    map[int]float64{},
+		index:   map[int]int{},
+	})
+}
+
+type exprQueue struct {
+	expr    *Expr
+	clients map[int]models.Client
+	debt    map[int]float64 // client ID -> allocations so far
+	items   []runtimeRequest
+	index   map[int]int // request ID -> vị trí trong items
+}
+
+func (q *exprQueue) score(req runtimeRequest, now int) float64 {
+	client := q.clients[req.ClientID]
+	return q.expr.Eval(&ExprEnv{
+		Priority: float64(req.Priority),
+		Wait:     float64(now - req.EnqueueTick),
+		Debt:     q.debt[req.ClientID],
+		Weight:   client.Weight,
+		Size:     float64(req.Size),
+		Class:    client.Class,
+	})
+}
+
+func (q *exprQueue) Push(req runtimeRequest) {
+	q.index[req.ID] = len(q.items)
+	q.items = append(q.items, req)
+}
+
+func (q *exprQueue) Pop(now int) (runtimeRequest, float64) {
+	best, bestScore := 0, q.score(q.items[0], now)
+	for i := 1; i < len(q.items); i++ {
+		score := q.score(q.items[i], now)
+		if keyedBefore(score, q.items[i], bestScore, q.items[best]) {
+			best, bestScore = i, score
+		}
+	}
+
+	selected := q.items[best]
+	q.remove(best)
+
+	return selected, bestScore
+}
+
+func (q *exprQueue) charge(req runtimeRequest, _ int, _ string, _ int) {
+	q.debt[req.ClientID]++
+}
+
+func (q *exprQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, req := range q.items {
+		fn(req, q.score(req, now))
+	}
+}
+
+func (q *exprQueue) Remove(id int) bool {
+	i, ok := q.index[id]
+	if !ok {
+		return false
+	}
+	q.remove(i)
+	return true
+}
+
+// remove swaps item i with the last one, order does not matter
+func (q *exprQueue) remove(i int) {
+	delete(q.index, q.items[i].ID)
+	last := len(q.items) - 1
+	if i != last {
+		q.items[i] = q.items[last]
+		q.index[q.items[i].ID] = i
+	}
+	q.items = q.items[:last]
+}
+
+func (q *exprQueue) Len() int {
+	return len(q.items)
+}
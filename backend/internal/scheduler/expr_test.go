@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestExprEval(t *testing.T) {
+	env := &ExprEnv{Priority: 2, Wait: 9, Debt: 3, Weight: 0.5, Size: 4, Class: "vip"}
+
+	tests := []struct {
+		source string
+		want   float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"2 ^ 3 ^ 2", 512}, // right associative
+		{"-2 ^ 2", -4},     // ^ binds tighter than unary -
+		{"priority * 10 + wait - debt / weight", 23},
+		{"sqrt(wait) * size", 12},
+		{"min(size, wait, 7) + max(1, priority)", 6},
+		{"abs(priority - wait)", 7},
+		{"exp(0) + log(1)", 1},
+		{"priority < 3", 1},
+		{"priority >= 3", 0},
+		{"size == 4", 1},
+		{`class == "vip"`, 1},
+		{`class != "vip"`, 0},
+		{`(class == "vip") * 100 - wait`, 91},
+		{"wait / (debt - 3)", 0}, // chia cho 0
+		{"(debt - 4) ^ 0.5", 0},  // NaN
+		{"0.25 * size", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			e, err := CompileExpr(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Eval(env); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// vô cực được giữ lại để vẫn so sánh được
+	e, err := CompileExpr("log(debt - 3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Eval(env); !math.IsInf(got, -1) {
+		t.Errorf("log(0) = %v, want -Inf", got)
+	}
+}
+
+func TestCompileExprErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"", `unexpected "end of expression" at 0`},
+		{"priority +", `unexpected "end of expression" at 10`},
+		{"1 # 2", `unexpected "#" at 2`},
+		{"1 < 2 < 3", `unexpected "<" at 6`},
+		{"1..2", `invalid number "1..2" at 0`},
+		{`class == "vip`, "unterminated string at 9"},
+		{"(1 + 2", "missing ) for ( at 0"},
+		{"age", `unknown variable "age" at 0`},
+		{"pow(2, 3)", `unknown function "pow" at 0`},
+		{"abs(1, 2)", "abs takes 1 argument(s), got 2"},
+		{"max(1 2)", "expected , or ) at 6"},
+		{"class", "expression must be a number, not a string"},
+		{"class + 1", `"+" at 6 needs numbers`},
+		{"sqrt(class)", `"sqrt" at 0 needs numbers`},
+		{"class == 1", `"==" at 6: class can only be compared with == or != to a string`},
+		{`class < "vip"`, `"<" at 6: class can only be compared`},
+		{strings.Repeat("(", 40) + "1" + strings.Repeat(")", 40), "nested deeper than 32"},
+		{strings.Repeat("-", 40) + "1", "nested deeper than 32"},
+		{strings.Repeat("1+", 300) + "1", "longer than 512 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := CompileExpr(tt.source)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExprStrategy(t *testing.T) {
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Priority: 1, Size: 1},
+			{ID: 2, Priority: 3, Size: 3},
+			{ID: 3, Priority: 2, Size: 2},
+		}
+	}
+
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{"-priority", []string{"0 select 1", "1 select 3", "2 select 2"}},
+		{"size", []string{"0 select 2", "1 select 3", "2 select 1"}},
+		// điểm bằng nhau thì theo ID
+		{"0", []string{"0 select 1", "1 select 2", "2 select 3"}},
+		{"size == 2", []string{"0 select 3", "1 select 1", "2 select 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			e, err := CompileExpr(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decisions := NewExprStrategy(e).Schedule(testWorkload(requests()...))
+			assertTimeline(t, timeline(decisions, ActionSelect), tt.want)
+		})
+	}
+}
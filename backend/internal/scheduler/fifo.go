@@ -0,0 +1,61 @@
+package scheduler
+
+// FIFOStrategy serves requests first-come-first-served.
+// runQueue already feeds the queue ordered by ArrivalAt then ID,
+// so the queue itself is a plain FIFO.
+type FIFOStrategy struct{}
+
+func NewFIFOStrategy() *FIFOStrategy {
+	return &FIFOStrategy{}
+}
+
+func (s *FIFOStrategy) Name() string {
+	return "fifo"
+}
+
+func (s *FIFOStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, newFIFOQueue())
+}
+
+type fifoQueue struct {
+	items   []runtimeRequest
+	members queueMembership
+}
+
+func newFIFOQueue() *fifoQueue {
+	return &fifoQueue{members: newQueueMembership()}
+}
+
+func (q *fifoQueue) Push(req runtimeRequest) {
+	q.items = append(q.items, req)
+	q.members.add(req.ID)
+}
+
+// Pop returns the oldest request; score is how long it waited
+func (q *fifoQueue) Pop(now int) (runtimeRequest, float64) {
+	for q.members.skip(q.items[0].ID) {
+		q.items = q.items[1:]
+	}
+
+	selected := q.items[0]
+	q.items = q.items[1:]
+	q.members.served(selected.ID)
+
+	return selected, float64(now - selected.EnqueueTick)
+}
+
+func (q *fifoQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, req := range q.items {
+		if q.members.queued[req.ID] {
+			fn(req, float64(now-req.EnqueueTick))
+		}
+	}
+}
+
+func (q *fifoQueue) Remove(id int) bool {
+	return q.members.remove(id)
+}
+
+func (q *fifoQueue) Len() int {
+	return q.members.len()
+}
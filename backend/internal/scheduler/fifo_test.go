@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestFIFO(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []models.Request
+		want     []string
+	}{
+		{
+			name: "arrival order, priority ignored",
+			requests: []models.Request{
+				{ID: 1, Priority: 3, ArrivalAt: 0},
+				{ID: 2, Priority: 1, ArrivalAt: 1},
+				{ID: 3, Priority: 2, ArrivalAt: 1},
+			},
+			want: []string{"0 select 1", "1 select 2", "2 select 3"},
+		},
+		{
+			name: "same tick by ID",
+			requests: []models.Request{
+				{ID: 3, ArrivalAt: 0},
+				{ID: 1, ArrivalAt: 0},
+				{ID: 2, ArrivalAt: 0},
+			},
+			want: []string{"0 select 1", "1 select 2", "2 select 3"},
+		},
+		{
+			name: "queue builds up behind a long request",
+			requests: []models.Request{
+				{ID: 1, ArrivalAt: 0, ServiceTime: 4},
+				{ID: 2, Priority: 1, ArrivalAt: 2},
+				{ID: 3, ArrivalAt: 1},
+			},
+			want: []string{"0 select 1", "4 select 3", "5 select 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions := NewFIFOStrategy().Schedule(testWorkload(tt.requests...))
+			assertTimeline(t, timeline(decisions, ActionSelect), tt.want)
+		})
+	}
+}
@@ -8,98 +8,250 @@ import (
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
 )
 
+// DefaultClasses is the catalog used when neither config.yaml nor the
+// request defines one: 10% VIP, 30% Paid, 60% Free
+func DefaultClasses() []models.ClientClass {
+	return []models.ClientClass{
+		{Name: "vip", Share: 0.10, Weight: 1.5, Priority: 1, MinRequests: 1, MaxRequests: 3},
+		{Name: "paid", Share: 0.30, Weight: 1.0, Priority: 2, MinRequests: 1, MaxRequests: 3},
+		{Name: "free", Share: 0.60, Weight: 0.7, Priority: 3, MinRequests: 1, MaxRequests: 3},
+	}
+}
+
+// MaxAttempts bounds a class retry policy, same limit as the request binding
+const MaxAttempts = 10
+
+// ValidateClasses checks a catalog before it is used by the generator
+func ValidateClasses(classes []models.ClientClass) error {
+	if len(classes) == 0 {
+		return fmt.Errorf("class catalog is empty")
+	}
+
+	seen := make(map[string]bool, len(classes))
+	for _, c := range classes {
+		switch {
+		case c.Name == "":
+			return fmt.Errorf("class name is required")
+		case seen[c.Name]:
+			return fmt.Errorf("duplicate class %q", c.Name)
+		case c.Share <= 0 || c.Weight <= 0:
+			return fmt.Errorf("class %q: share and weight must be > 0", c.Name)
+		case c.Priority < 1:
+			return fmt.Errorf("class %q: priority must be >= 1", c.Name)
+		case c.MinRequests < 1 || c.MaxRequests < c.MinRequests:
+			return fmt.Errorf("class %q: need 1 <= min_requests <= max_requests", c.Name)
+		case c.TTL < 0:
+			return fmt.Errorf("class %q: ttl must be >= 0", c.Name)
+		case c.MinSize < 0 || (c.MaxSize != 0 && c.MaxSize < c.MinSize):
+			return fmt.Errorf("class %q: need 0 <= min_size <= max_size", c.Name)
+		case c.MinService < 0 || (c.MaxService != 0 && c.MaxService < c.MinService):
+			return fmt.Errorf("class %q: need 0 <= min_service <= max_service", c.Name)
+		case c.MaxAttempts < 0 || c.MaxAttempts > MaxAttempts || c.RetryBackoff < 0 || c.RetryJitter < 0 || c.RetryJitter > 1:
+			return fmt.Errorf("class %q: need 0 <= max_attempts <= %d, retry_backoff >= 0 and 0 <= retry_jitter <= 1", c.Name, MaxAttempts)
+		case c.MinPatience < 0 || (c.MaxPatience != 0 && c.MaxPatience < c.MinPatience):
+			return fmt.Errorf("class %q: need 0 <= min_patience <= max_patience", c.Name)
+		}
+		for r, d := range c.Demand {
+			if d < 0 {
+				return fmt.Errorf("class %q: demand %s must be >= 0", c.Name, r)
+			}
+		}
+		seen[c.Name] = true
+	}
+
+	return nil
+}
+
+// GenerateClients rolls each client's class from the catalog shares
 func GenerateClients(
 	seed int64,
 	totalClients int,
+	classes []models.ClientClass,
 ) []models.Client {
 
 	rng := rand.New(rand.NewSource(seed))
 
+	// cumulative share, chuẩn hoá để tổng = 1
+	var totalShare float64
+	for _, c := range classes {
+		totalShare += c.Share
+	}
+	cumulative := make([]float64, len(classes))
+	var acc float64
+	for i, c := range classes {
+		acc += c.Share
+		cumulative[i] = acc / totalShare
+	}
+
 	clients := make([]models.Client, 0, totalClients)
 
 	for i := 0; i < totalClients; i++ {
 		roll := rng.Float64()
 
-		client := models.Client{
-			ID: i + 1,
-		}
-
-		// Distribution:
-		// 10% VIP, 30% Paid, 60% Free
-		switch {
-		case roll < 0.10:
-			client.Class = "vip"
-			client.Weight = 1.5
-		case roll < 0.40:
-			client.Class = "paid"
-			client.Weight = 1.0
-		default:
-			client.Class = "free"
-			client.Weight = 0.7
+		class := classes[len(classes)-1]
+		for j, limit := range cumulative {
+			if roll < limit {
+				class = classes[j]
+				break
+			}
 		}
 
-		clients = append(clients, client)
-	}
-	for _, c := range clients {
-		fmt.Printf("ID=%d | class=%s | weight=%.1f\n",
-			c.ID, c.Class, c.Weight,
-		)
+		clients = append(clients, models.Client{
+			ID:     i + 1,
+			Class:  class.Name,
+			Weight: class.Weight,
+		})
 	}
 
 	return clients
 }
-func priorityFromClass(class string) int {
-	switch class {
-	case "vip":
-		return 1
-	case "paid":
-		return 2
-	default:
-		return 3
-	}
-}
 
 // GenerateRequests sinh request từ danh sách client
-// - mỗi client gửi 1–3 request
-// - arrival có burst (Gaussian-like)
-// - priority theo client class
+// - mỗi client gửi MinRequests–MaxRequests request theo class
+// - arrival theo ArrivalModel (nil = burst Gaussian quanh tick 50)
+// - priority theo class
+// - deadline = arrival + class.TTL nếu class có TTL
+// - size đều trong [MinSize, MaxSize], rng riêng để không đổi arrival
+// - service time đều trong [MinService, MaxService], rng riêng
+// - patience đều trong [MinPatience, MaxPatience], rng riêng, 0 = chờ mãi
 func GenerateRequests(
 	clients []models.Client,
 	seed int64,
+	classes []models.ClientClass,
+	arrival ArrivalModel,
 ) ([]models.Request, []models.ClientArrival) {
 
 	rng := rand.New(rand.NewSource(seed + 1))
+	if arrival == nil {
+		arrival = defaultArrival()
+	}
+
+	classByName := make(map[string]models.ClientClass, len(classes))
+	for _, c := range classes {
+		classByName[c.Name] = c
+	}
 
 	var requests []models.Request
 	reqID := 1
 
 	for _, c := range clients {
-		reqCount := rng.Intn(3) + 1
+		class := classByName[c.Class]
+		reqCount := rng.Intn(class.MaxRequests-class.MinRequests+1) + class.MinRequests
 
 		for i := 0; i < reqCount; i++ {
 			req := models.Request{
 				ID:        reqID,
 				ClientID:  c.ID,
-				Priority:  priorityFromClass(c.Class),
-				ArrivalAt: gaussianArrival(rng),
+				Priority:  class.Priority,
+				ArrivalAt: arrival.Next(rng),
 			}
 			requests = append(requests, req)
 			reqID++
 		}
 	}
 
-	// sort theo arrival time
+	if process, ok := arrival.(arrivalProcess); ok {
+		timeline := process.Timeline(rng, len(requests))
+		rng.Shuffle(len(timeline), func(i, j int) {
+			timeline[i], timeline[j] = timeline[j], timeline[i]
+		})
+		for i := range requests {
+			requests[i].ArrivalAt = timeline[i]
+		}
+	}
+
+	clientByID := make(map[int]models.Client, len(clients))
+	for _, c := range clients {
+		clientByID[c.ID] = c
+	}
+	// seed+2 là của lottery
+	sizeRng := rand.New(rand.NewSource(seed + 3))
+	serviceRng := rand.New(rand.NewSource(seed + 4))
+	patienceRng := rand.New(rand.NewSource(seed + 6)) // seed+5 là của admission early_drop
+	for i := range requests {
+		class := classByName[clientByID[requests[i].ClientID].Class]
+		requests[i].Pools = class.Pools
+		requests[i].Resources = class.Demand
+		if class.TTL > 0 {
+			requests[i].Deadline = requests[i].ArrivalAt + class.TTL
+		}
+
+		minSize, maxSize := sizeRange(class)
+		requests[i].Size = minSize
+		if maxSize > minSize {
+			requests[i].Size += sizeRng.Intn(maxSize - minSize + 1)
+		}
+
+		minService, maxService := serviceRange(class)
+		requests[i].ServiceTime = minService
+		if maxService > minService {
+			requests[i].ServiceTime += serviceRng.Intn(maxService - minService + 1)
+		}
+
+		minPatience, maxPatience := patienceRange(class)
+		requests[i].Patience = minPatience
+		if maxPatience > minPatience {
+			requests[i].Patience += patienceRng.Intn(maxPatience - minPatience + 1)
+		}
+	}
+
+	sortRequests(requests)
+
+	return requests, buildArrivalOrder(clients, requests)
+}
+
+// sizeRange is the request size bounds of a class, unset means 1 unit
+func sizeRange(class models.ClientClass) (int, int) {
+	minSize, maxSize := class.MinSize, class.MaxSize
+	if minSize < 1 {
+		minSize = 1
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+	return minSize, maxSize
+}
+
+// serviceRange is the service time bounds of a class, unset means 1 tick
+func serviceRange(class models.ClientClass) (int, int) {
+	minService, maxService := class.MinService, class.MaxService
+	if minService < 1 {
+		minService = 1
+	}
+	if maxService < minService {
+		maxService = minService
+	}
+	return minService, maxService
+}
+
+// patienceRange is the patience bounds of a class, unset means forever (0)
+func patienceRange(class models.ClientClass) (int, int) {
+	minPatience, maxPatience := class.MinPatience, class.MaxPatience
+	if maxPatience == 0 {
+		return minPatience, minPatience
+	}
+	if minPatience < 1 {
+		minPatience = 1
+	}
+	return minPatience, maxPatience
+}
+
+// sortRequests sort theo arrival time, cùng tick thì theo ID
+func sortRequests(requests []models.Request) {
 	sort.Slice(requests, func(i, j int) bool {
 		if requests[i].ArrivalAt != requests[j].ArrivalAt {
 			return requests[i].ArrivalAt < requests[j].ArrivalAt
 		}
 		return requests[i].ID < requests[j].ID
 	})
+}
 
-	// === BUILD ARRIVAL ORDER ===
-	clientMap := make(map[int]models.Client)
+// buildArrivalOrder lists clients by the tick of their first request,
+// requests must already be sorted
+func buildArrivalOrder(clients []models.Client, requests []models.Request) []models.ClientArrival {
+	clientByID := make(map[int]models.Client, len(clients))
 	for _, c := range clients {
-		clientMap[c.ID] = c
+		clientByID[c.ID] = c
 	}
 
 	seen := make(map[int]bool)
@@ -110,7 +262,7 @@ func GenerateRequests(
 			continue
 		}
 
-		c := clientMap[r.ClientID]
+		c := clientByID[r.ClientID]
 		arrivalOrder = append(arrivalOrder, models.ClientArrival{
 			ClientID:  c.ID,
 			Class:     c.Class,
@@ -120,15 +272,5 @@ func GenerateRequests(
 		seen[r.ClientID] = true
 	}
 
-	return requests, arrivalOrder
-}
-
-func gaussianArrival(rng *rand.Rand) int {
-	// Gaussian centered around tick = 50
-	v := int(rng.NormFloat64()*10 + 50)
-
-	if v < 0 {
-		return 0
-	}
-	return v
+	return arrivalOrder
 }
@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestValidateClasses(t *testing.T) {
+	valid := func() models.ClientClass {
+		return models.ClientClass{Name: "free", Share: 1, Weight: 1, Priority: 1, MinRequests: 1, MaxRequests: 2}
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(c *models.ClientClass)
+		wantErr string
+	}{
+		{name: "valid", edit: func(c *models.ClientClass) {}},
+		{name: "no name", edit: func(c *models.ClientClass) { c.Name = "" }, wantErr: "class name is required"},
+		{name: "zero share", edit: func(c *models.ClientClass) { c.Share = 0 }, wantErr: "share and weight must be > 0"},
+		{name: "zero weight", edit: func(c *models.ClientClass) { c.Weight = 0 }, wantErr: "share and weight must be > 0"},
+		{name: "priority", edit: func(c *models.ClientClass) { c.Priority = 0 }, wantErr: "priority must be >= 1"},
+		{name: "requests", edit: func(c *models.ClientClass) { c.MinRequests = 3 }, wantErr: "need 1 <= min_requests <= max_requests"},
+		{name: "ttl", edit: func(c *models.ClientClass) { c.TTL = -1 }, wantErr: "ttl must be >= 0"},
+		{name: "size", edit: func(c *models.ClientClass) { c.MinSize, c.MaxSize = 4, 2 }, wantErr: "need 0 <= min_size <= max_size"},
+		{name: "service", edit: func(c *models.ClientClass) { c.MinService, c.MaxService = 4, 2 }, wantErr: "need 0 <= min_service <= max_service"},
+		{name: "attempts", edit: func(c *models.ClientClass) { c.MaxAttempts = 11 }, wantErr: "need 0 <= max_attempts <= 10"},
+		{name: "jitter", edit: func(c *models.ClientClass) { c.RetryJitter = 1.5 }, wantErr: "0 <= retry_jitter <= 1"},
+		{name: "patience", edit: func(c *models.ClientClass) { c.MinPatience, c.MaxPatience = 4, 2 }, wantErr: "need 0 <= min_patience <= max_patience"},
+		{name: "demand", edit: func(c *models.ClientClass) { c.Demand = map[string]int{"cpu": -1} }, wantErr: "demand cpu must be >= 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.edit(&c)
+			err := ValidateClasses([]models.ClientClass{c})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := ValidateClasses(nil); err == nil || err.Error() != "class catalog is empty" {
+		t.Errorf("empty catalog: err = %v", err)
+	}
+	if err := ValidateClasses([]models.ClientClass{valid(), valid()}); err == nil || !strings.Contains(err.Error(), `duplicate class "free"`) {
+		t.Errorf("duplicate: err = %v", err)
+	}
+}
+
+func TestGenerateClients(t *testing.T) {
+	// share không cần cộng lại bằng 1
+	classes := []models.ClientClass{
+		{Name: "gold", Share: 1, Weight: 4},
+		{Name: "bronze", Share: 3, Weight: 0.5},
+	}
+	clients := GenerateClients(9, 20000, classes)
+
+	counts := map[string]int{}
+	for i, c := range clients {
+		if c.ID != i+1 {
+			t.Fatalf("client %d has ID %d", i, c.ID)
+		}
+		if want := map[string]float64{"gold": 4, "bronze": 0.5}[c.Class]; c.Weight != want {
+			t.Errorf("client %d (%s): weight = %v, want %v", c.ID, c.Class, c.Weight, want)
+		}
+		counts[c.Class]++
+	}
+	if got := float64(counts["gold"]) / float64(len(clients)); math.Abs(got-0.25) > 0.02 {
+		t.Errorf("gold share = %.3f, want about 0.25", got)
+	}
+	if !reflect.DeepEqual(clients, GenerateClients(9, 20000, classes)) {
+		t.Error("same seed, different clients")
+	}
+}
+
+func TestGenerateRequests(t *testing.T) {
+	classes := []models.ClientClass{
+		{
+			Name: "vip", Share: 1, Weight: 2, Priority: 1, MinRequests: 2, MaxRequests: 4, TTL: 30,
+			MinSize: 2, MaxSize: 5, MinService: 3, MaxService: 6, MinPatience: 10, MaxPatience: 20,
+			Pools: []string{"gpu"},
+		},
+		{Name: "free", Share: 1, Weight: 1, Priority: 3, MinRequests: 1, MaxRequests: 1},
+	}
+	clients := GenerateClients(4, 300, classes)
+	requests, order := GenerateRequests(clients, 4, classes, nil)
+
+	classOf := map[int]string{}
+	for _, c := range clients {
+		classOf[c.ID] = c.Class
+	}
+	perClient := map[int]int{}
+	for _, r := range requests {
+		perClient[r.ClientID]++
+		within := func(name string, v, lo, hi int) {
+			if v < lo || v > hi {
+				t.Errorf("request %d (%s): %s = %d, want in [%d, %d]", r.ID, classOf[r.ClientID], name, v, lo, hi)
+			}
+		}
+		switch classOf[r.ClientID] {
+		case "vip":
+			within("priority", r.Priority, 1, 1)
+			within("size", r.Size, 2, 5)
+			within("service_time", r.ServiceTime, 3, 6)
+			within("patience", r.Patience, 10, 20)
+			within("deadline", r.Deadline, r.ArrivalAt+30, r.ArrivalAt+30)
+			if !reflect.DeepEqual(r.Pools, []string{"gpu"}) {
+				t.Errorf("request %d: pools = %v", r.ID, r.Pools)
+			}
+		case "free":
+			// không khai báo thì 1 unit, 1 tick, chờ mãi, không deadline
+			within("priority", r.Priority, 3, 3)
+			within("size", r.Size, 1, 1)
+			within("service_time", r.ServiceTime, 1, 1)
+			within("patience", r.Patience, 0, 0)
+			within("deadline", r.Deadline, 0, 0)
+		}
+	}
+	for id, n := range perClient {
+		lo, hi := 1, 1
+		if classOf[id] == "vip" {
+			lo, hi = 2, 4
+		}
+		if n < lo || n > hi {
+			t.Errorf("client %d (%s) sent %d requests, want in [%d, %d]", id, classOf[id], n, lo, hi)
+		}
+	}
+	if !sort.SliceIsSorted(requests, func(i, j int) bool { return requests[i].ArrivalAt < requests[j].ArrivalAt }) {
+		t.Error("requests are not sorted by arrival")
+	}
+	if len(order) != len(clients) {
+		t.Errorf("arrival order has %d clients, want %d", len(order), len(clients))
+	}
+
+	// size, service time và patience có rng riêng: đổi range không đổi arrival
+	wide := append([]models.ClientClass(nil), classes...)
+	wide[0].MaxSize, wide[0].MaxService, wide[0].MaxPatience = 50, 50, 50
+	again, _ := GenerateRequests(clients, 4, wide, nil)
+	for i := range requests {
+		if again[i].ID != requests[i].ID || again[i].ArrivalAt != requests[i].ArrivalAt {
+			t.Fatalf("request %d: arrival changed with the size range", requests[i].ID)
+		}
+	}
+}
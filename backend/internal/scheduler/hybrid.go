@@ -1,7 +1,8 @@
 package scheduler
 
 import (
-	"sort"
+	"container/heap"
+	"math"
 
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
 )
@@ -10,9 +11,9 @@ type HybridStrategy struct {
 	cfg HybridConfig
 }
 
-func NewHybridStrategy() *HybridStrategy {
+func NewHybridStrategy(cfg HybridConfig) *HybridStrategy {
 	return &HybridStrategy{
-		cfg: HybridConfig{Alpha: 10, Beta: 1, Gamma: 2},
+		cfg: cfg,
 	}
 }
 
@@ -20,47 +21,278 @@ func (s *HybridStrategy) Name() string {
 	return "hybrid"
 }
 
-func (s *HybridStrategy) Schedule(requests []models.Request) []Decision {
-	var decisions []Decision
-	var queue []runtimeRequest
-	clientDebt := map[int]float64{}
+func (s *HybridStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, newHybridQueue(s.cfg, w.Clients))
+}
+
+// hybridQueue ranks requests by computeScore in O(log n) per operation.
+//
+// score = Priority*Alpha + (now-EnqueueTick)*Beta - debt*Gamma
+//
+//	= [Priority*Alpha - EnqueueTick*Beta] + now*Beta - debt*Gamma
+//
+// now*Beta is the same for every queued request, so it never changes the
+// order. The bracket is fixed at enqueue time and debt only changes for the
+// client that was just served. Requests are therefore kept in one bucket per
+// client (ordered by the fixed part) and clients in an indexed heap keyed by
+// their best request minus their debt; serving a request re-keys one client.
+//
+// Debt is charged through the engine's charge hook, only for requests that
+// were actually allocated.
+//
+// Debt decay is applied lazily once per tick in advance: a windowed debt
+// re-keys only the clients whose charges left the window, an exponential
+// decay shrinks every debt by the same factor but not the fixed part, so all
+// clients are re-keyed (O(clients) per tick instead of O(n log n)).
+type hybridQueue struct {
+	cfg     HybridConfig
+	weights map[int]float64 // client ID -> Client.Weight, only with WeightedDebt
+	buckets map[int]*clientBucket
+	clients clientHeap
+	members queueMembership
+	owner   map[int]int // request ID -> client ID
+	charges []debtCharge
+	now     int // tick the debts are decayed to
+}
+
+// debtCharge is one allocation still counted by DebtDecayWindow
+type debtCharge struct {
+	tick     int
+	clientID int
+	amount   float64
+}
+
+func newHybridQueue(cfg HybridConfig, clients map[int]models.Client) *hybridQueue {
+	q := &hybridQueue{
+		cfg:     cfg,
+		weights: map[int]float64{},
+		buckets: map[int]*clientBucket{},
+		members: newQueueMembership(),
+		owner:   map[int]int{},
+	}
+	if cfg.WeightedDebt {
+		for id, c := range clients {
+			q.weights[id] = c.Weight
+		}
+	}
+	return q
+}
 
-	reqIdx := 0
-	tick := 0
+// chargeAmount is the debt added to a client for one allocation
+func (q *hybridQueue) chargeAmount(clientID int) float64 {
+	if w := q.weights[clientID]; w > 0 {
+		return 1 / w
+	}
+	return 1
+}
 
-	for reqIdx < len(requests) || len(queue) > 0 {
+// advance decays every debt to tick now
+func (q *hybridQueue) advance(now int) {
+	if now <= q.now {
+		return
+	}
+	elapsed := now - q.now
+	q.now = now
 
-		for reqIdx < len(requests) && requests[reqIdx].ArrivalAt <= tick {
-			queue = append(queue, runtimeRequest{
-				Request:     requests[reqIdx],
-				EnqueueTick: tick,
-			})
-			reqIdx++
+	switch q.cfg.DebtDecay {
+	case DebtDecayExponential:
+		factor := math.Pow(0.5, float64(elapsed)/q.cfg.DebtHalfLife)
+		for _, b := range q.buckets {
+			b.debt *= factor
+		}
+		for _, b := range q.clients.items {
+			b.key = b.requests.items[0].key - b.debt*q.cfg.Gamma
 		}
+		heap.Init(&q.clients)
 
-		if len(queue) == 0 {
-			tick++
-			continue
+	case DebtDecayWindow:
+		for len(q.charges) > 0 && q.charges[0].tick <= now-q.cfg.DebtWindow {
+			c := q.charges[0]
+			q.charges = q.charges[1:]
+
+			b := q.buckets[c.clientID]
+			b.debt -= c.amount
+			if b.debt < 1e-9 {
+				b.debt = 0 // sai số float
+			}
+			q.rekey(b)
 		}
+	}
+}
+
+func (q *hybridQueue) Push(req runtimeRequest) {
+	q.advance(req.EnqueueTick)
 
-		sort.Slice(queue, func(i, j int) bool {
-			return computeScore(queue[i], tick, clientDebt, s.cfg) >
-				computeScore(queue[j], tick, clientDebt, s.cfg)
-		})
+	b, ok := q.buckets[req.ClientID]
+	if !ok {
+		b = &clientBucket{clientID: req.ClientID, index: -1}
+		q.buckets[req.ClientID] = b
+	}
+
+	heap.Push(&b.requests, keyedRequest{
+		runtimeRequest: req,
+		key:            float64(req.Priority)*q.cfg.Alpha - float64(req.EnqueueTick)*q.cfg.Beta,
+	})
+	q.members.add(req.ID)
+	q.owner[req.ID] = req.ClientID
+
+	q.rekey(b)
+}
+
+func (q *hybridQueue) Pop(now int) (runtimeRequest, float64) {
+	q.advance(now)
+
+	b := q.clients.items[0]
+	selected := heap.Pop(&b.requests).(keyedRequest).runtimeRequest
+	score := computeScore(selected, now, b.debt, q.cfg)
+
+	q.members.served(selected.ID)
+	delete(q.owner, selected.ID)
+
+	q.rekey(b)
+
+	return selected, score
+}
 
-		selected := queue[0]
-		score := computeScore(selected, tick, clientDebt, s.cfg)
+// charge adds the debt of an allocation to the client of req
+func (q *hybridQueue) charge(req runtimeRequest, now int, _ string, _ int) {
+	q.advance(now)
 
-		decisions = append(decisions, Decision{
-			Tick:    tick,
-			Request: selected.Request,
-			Score:   score,
-		})
+	b := q.buckets[req.ClientID]
+	amount := q.chargeAmount(req.ClientID)
+	b.debt += amount
+	if q.cfg.DebtDecay == DebtDecayWindow {
+		q.charges = append(q.charges, debtCharge{tick: now, clientID: req.ClientID, amount: amount})
+	}
 
-		clientDebt[selected.ClientID]++
-		queue = queue[1:]
-		tick++
+	q.rekey(b)
+}
+
+func (q *hybridQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	q.advance(now)
+	for _, b := range q.clients.items {
+		for _, item := range b.requests.items {
+			if q.members.queued[item.ID] {
+				fn(item.runtimeRequest, computeScore(item.runtimeRequest, now, b.debt, q.cfg))
+			}
+		}
 	}
+}
+
+func (q *hybridQueue) Remove(id int) bool {
+	if !q.members.remove(id) {
+		return false
+	}
+
+	clientID := q.owner[id]
+	delete(q.owner, id)
+	q.rekey(q.buckets[clientID])
+
+	return true
+}
+
+func (q *hybridQueue) Len() int {
+	return q.members.len()
+}
+
+// rekey restores the client heap after bucket b changed.
+// Removed requests are discarded once they reach the top of their bucket.
+func (q *hybridQueue) rekey(b *clientBucket) {
+	for b.requests.Len() > 0 && q.members.skip(b.requests.items[0].ID) {
+		heap.Pop(&b.requests)
+	}
+
+	switch {
+	case b.requests.Len() == 0 && b.index >= 0:
+		heap.Remove(&q.clients, b.index)
+	case b.requests.Len() == 0:
+	case b.index < 0:
+		b.key = b.requests.items[0].key - b.debt*q.cfg.Gamma
+		heap.Push(&q.clients, b)
+	default:
+		b.key = b.requests.items[0].key - b.debt*q.cfg.Gamma
+		heap.Fix(&q.clients, b.index)
+	}
+}
+
+type keyedRequest struct {
+	runtimeRequest
+	key float64 // phần score không đổi theo thời gian
+}
+
+// keyedBefore is the total order of the hybrid queue:
+// higher key first, then earlier EnqueueTick, then lower ID
+func keyedBefore(aKey float64, a runtimeRequest, bKey float64, b runtimeRequest) bool {
+	if aKey != bKey {
+		return aKey > bKey
+	}
+	if a.EnqueueTick != b.EnqueueTick {
+		return a.EnqueueTick < b.EnqueueTick
+	}
+	return a.ID < b.ID
+}
+
+// requestHeap is a max-heap of one client's requests
+type requestHeap struct {
+	items []keyedRequest
+}
+
+func (h *requestHeap) Len() int { return len(h.items) }
+
+func (h *requestHeap) Less(i, j int) bool {
+	return keyedBefore(h.items[i].key, h.items[i].runtimeRequest, h.items[j].key, h.items[j].runtimeRequest)
+}
+
+func (h *requestHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *requestHeap) Push(x any) { h.items = append(h.items, x.(keyedRequest)) }
+
+func (h *requestHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
+
+type clientBucket struct {
+	clientID int
+	debt     float64
+	key      float64 // best request key - debt*Gamma
+	requests requestHeap
+	index    int // vị trí trong clientHeap, -1 nếu không có request nào
+}
+
+// clientHeap is an indexed max-heap of clients with queued requests
+type clientHeap struct {
+	items []*clientBucket
+}
+
+func (h *clientHeap) Len() int { return len(h.items) }
+
+func (h *clientHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	return keyedBefore(a.key, a.requests.items[0].runtimeRequest, b.key, b.requests.items[0].runtimeRequest)
+}
+
+func (h *clientHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *clientHeap) Push(x any) {
+	b := x.(*clientBucket)
+	b.index = len(h.items)
+	h.items = append(h.items, b)
+}
 
-	return decisions
+func (h *clientHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	b := old[n-1]
+	old[n-1] = nil
+	b.index = -1
+	h.items = old[:n-1]
+	return b
 }
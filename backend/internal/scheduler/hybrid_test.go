@@ -0,0 +1,256 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+var defaultHybrid = HybridConfig{Alpha: 10, Beta: 1, Gamma: 2}
+
+// baselineHybrid is the sort-based Schedule loop hybrid had before the
+// discrete-event engine: one request per tick, sorted by score every tick.
+// sort.Slice left equal scores in no particular order, here they are
+// broken like the heap does: earlier EnqueueTick, then lower ID.
+func baselineHybrid(requests []models.Request, cfg HybridConfig) []Decision {
+	var decisions []Decision
+	var queue []runtimeRequest
+	clientDebt := map[int]float64{}
+
+	reqIdx := 0
+	tick := 0
+
+	for reqIdx < len(requests) || len(queue) > 0 {
+
+		for reqIdx < len(requests) && requests[reqIdx].ArrivalAt <= tick {
+			queue = append(queue, runtimeRequest{
+				Request:     requests[reqIdx],
+				EnqueueTick: tick,
+			})
+			reqIdx++
+		}
+
+		if len(queue) == 0 {
+			tick++
+			continue
+		}
+
+		sort.Slice(queue, func(i, j int) bool {
+			return keyedBefore(
+				computeScore(queue[i], tick, clientDebt[queue[i].ClientID], cfg), queue[i],
+				computeScore(queue[j], tick, clientDebt[queue[j].ClientID], cfg), queue[j],
+			)
+		})
+
+		selected := queue[0]
+		score := computeScore(selected, tick, clientDebt[selected.ClientID], cfg)
+
+		decisions = append(decisions, Decision{
+			Tick:    tick,
+			Request: selected.Request,
+			Score:   score,
+			Action:  ActionSelect,
+		})
+
+		clientDebt[selected.ClientID]++
+		queue = queue[1:]
+		tick++
+	}
+
+	return decisions
+}
+
+func generatedWorkload(seed int64, clients int) Workload {
+	classes := DefaultClasses()
+	cs := GenerateClients(seed, clients, classes)
+	requests, _ := GenerateRequests(cs, seed, classes, nil)
+	return NewWorkload(cs, requests, seed)
+}
+
+func TestHybridMatchesBaseline(t *testing.T) {
+	tests := []struct {
+		seed    int64
+		clients int
+	}{
+		{1, 50},
+		{7, 300},
+		{42, 300},
+		{7, 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("seed=%d/clients=%d", tt.seed, tt.clients), func(t *testing.T) {
+			w := generatedWorkload(tt.seed, tt.clients)
+			want := baselineHybrid(w.Requests, defaultHybrid)
+
+			var got []Decision
+			for _, d := range NewHybridStrategy(defaultHybrid).Schedule(w) {
+				if d.Action == ActionSelect {
+					got = append(got, d)
+				}
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("got %d selections, want %d", len(got), len(want))
+			}
+			for i := range want {
+				g, b := got[i], want[i]
+				if g.Tick != b.Tick || g.Request.ID != b.Request.ID || g.Score != b.Score {
+					t.Fatalf("selection %d: got tick=%d id=%d score=%v, want tick=%d id=%d score=%v",
+						i, g.Tick, g.Request.ID, g.Score, b.Tick, b.Request.ID, b.Score)
+				}
+			}
+		})
+	}
+}
+
+func TestHybridDebt(t *testing.T) {
+	// client 1 has 3 requests, client 2 one; same priority and arrival
+	requests := []models.Request{
+		{ID: 1, ClientID: 1, Priority: 1},
+		{ID: 2, ClientID: 1, Priority: 1},
+		{ID: 3, ClientID: 1, Priority: 1},
+		{ID: 4, ClientID: 2, Priority: 1},
+	}
+
+	tests := []struct {
+		name string
+		cfg  HybridConfig
+		want []string
+	}{
+		{
+			// debt of client 1 pushes request 4 ahead of its second request
+			name: "fairness penalty",
+			cfg:  HybridConfig{Alpha: 10, Beta: 1, Gamma: 2},
+			want: []string{"0 select 1", "1 select 4", "2 select 2", "3 select 3"},
+		},
+		{
+			name: "no penalty keeps arrival order",
+			cfg:  HybridConfig{Alpha: 10, Beta: 1},
+			want: []string{"0 select 1", "1 select 2", "2 select 3", "3 select 4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions := NewHybridStrategy(tt.cfg).Schedule(testWorkload(requests...))
+			assertTimeline(t, timeline(decisions, ActionSelect), tt.want)
+		})
+	}
+}
+
+func TestHybridDebtWeightAndDecay(t *testing.T) {
+	// client 1 was served at ticks 0 and 1, client 2 at tick 3; at tick 5
+	// both have a request waiting and only their debt differs
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, ClientID: 1, Priority: 1, ArrivalAt: 0},
+			{ID: 2, ClientID: 1, Priority: 1, ArrivalAt: 1},
+			{ID: 3, ClientID: 2, Priority: 1, ArrivalAt: 3},
+			{ID: 4, ClientID: 2, Priority: 1, ArrivalAt: 5},
+			{ID: 5, ClientID: 1, Priority: 1, ArrivalAt: 5},
+		}
+	}
+	base := HybridConfig{Alpha: 10, Gamma: 2}
+
+	tests := []struct {
+		name    string
+		cfg     func(c HybridConfig) HybridConfig
+		weights map[int]float64
+		want    []string
+	}{
+		{
+			name: "debt never decays",
+			cfg:  func(c HybridConfig) HybridConfig { return c },
+			want: []string{"5 select 4", "6 select 5"},
+		},
+		{
+			// client 1 pays 1/4 per allocation: 0.5 against 1
+			name:    "weighted debt",
+			cfg:     func(c HybridConfig) HybridConfig { c.WeightedDebt = true; return c },
+			weights: map[int]float64{1: 4, 2: 1},
+			want:    []string{"5 select 5", "6 select 4"},
+		},
+		{
+			// 2^-5 + 2^-4 against 2^-2
+			name: "exponential",
+			cfg: func(c HybridConfig) HybridConfig {
+				c.DebtDecay, c.DebtHalfLife = DebtDecayExponential, 1
+				return c
+			},
+			want: []string{"5 select 5", "6 select 4"},
+		},
+		{
+			// allocations of ticks 0-2 left the window
+			name: "window",
+			cfg: func(c HybridConfig) HybridConfig {
+				c.DebtDecay, c.DebtWindow = DebtDecayWindow, 3
+				return c
+			},
+			want: []string{"5 select 5", "6 select 4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			for id, weight := range tt.weights {
+				c := w.Clients[id]
+				c.Weight = weight
+				w.Clients[id] = c
+			}
+			decisions := NewHybridStrategy(tt.cfg(base)).Schedule(w)
+			got := timeline(decisions, ActionSelect)
+			assertTimeline(t, got[len(got)-2:], tt.want)
+		})
+	}
+}
+
+func TestHybridConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HybridConfig
+		wantErr bool
+	}{
+		{"default", HybridConfig{}, false},
+		{"none", HybridConfig{DebtDecay: DebtDecayNone}, false},
+		{"exponential", HybridConfig{DebtDecay: DebtDecayExponential, DebtHalfLife: 10}, false},
+		{"exponential without half life", HybridConfig{DebtDecay: DebtDecayExponential}, true},
+		{"window", HybridConfig{DebtDecay: DebtDecayWindow, DebtWindow: 5}, false},
+		{"window without size", HybridConfig{DebtDecay: DebtDecayWindow}, true},
+		{"unknown", HybridConfig{DebtDecay: "linear"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func benchmarkHybrid(b *testing.B, clients int) {
+	w := generatedWorkload(7, clients)
+	s := NewHybridStrategy(defaultHybrid)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Schedule(w)
+	}
+}
+
+// benchmarkBaseline is the sort-per-tick loop on the same workload
+func benchmarkBaseline(b *testing.B, clients int) {
+	w := generatedWorkload(7, clients)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		baselineHybrid(w.Requests, defaultHybrid)
+	}
+}
+
+func BenchmarkHybrid50(b *testing.B)   { benchmarkHybrid(b, 50) }
+func BenchmarkHybrid300(b *testing.B)  { benchmarkHybrid(b, 300) }
+func BenchmarkHybrid2000(b *testing.B) { benchmarkHybrid(b, 2000) }
+
+func BenchmarkHybridBaseline50(b *testing.B)   { benchmarkBaseline(b, 50) }
+func BenchmarkHybridBaseline300(b *testing.B)  { benchmarkBaseline(b, 300) }
+func BenchmarkHybridBaseline2000(b *testing.B) { benchmarkBaseline(b, 2000) }
@@ -0,0 +1,92 @@
+package scheduler
+
+import "github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+
+// inventory is the capacity runQueue hands out as it selects requests.
+// It mirrors what SlotStore will do with the decisions, so the engine can
+// reject a request that cannot be served instead of selecting it.
+type inventory interface {
+	// fits reports whether req can be served in full
+	fits(req models.Request) bool
+	// canTake reports whether req would get anything under fill
+	canTake(req models.Request, fill FillMode) bool
+	// take charges req, returns the pool and the units granted, 0 if rejected
+	take(req models.Request, fill FillMode) (string, int)
+	// empty reports whether nothing is left to hand out
+	empty() bool
+	// capacity is the total of each pool or resource
+	capacity() map[string]int
+}
+
+// capacityAwareQueue is a readyQueue that reads the capacity left while
+// choosing, e.g. to skip requests that no longer fit
+type capacityAwareQueue interface {
+	readyQueue
+	useInventory(inv inventory)
+}
+
+// newInventory is the ledger of the capacity w declares, nil if unknown
+func newInventory(w Workload) inventory {
+	switch {
+	case len(w.Resources) > 0:
+		return newResourceLedger(w.Resources)
+	case len(w.Pools) > 0:
+		return newPoolLedger(w.Pools)
+	}
+	return nil
+}
+
+// resourceLedger tracks the capacity vector left in a multi-resource run.
+// Requests are all-or-nothing over their demand vector.
+type resourceLedger struct {
+	total     map[string]int
+	remaining map[string]int
+}
+
+func newResourceLedger(resources map[string]int) *resourceLedger {
+	l := &resourceLedger{
+		total:     make(map[string]int, len(resources)),
+		remaining: make(map[string]int, len(resources)),
+	}
+	for r, c := range resources {
+		l.total[r] = c
+		l.remaining[r] = c
+	}
+	return l
+}
+
+func (l *resourceLedger) fits(req models.Request) bool {
+	for r, d := range req.Resources {
+		if l.remaining[r] < d {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *resourceLedger) canTake(req models.Request, _ FillMode) bool {
+	return l.fits(req)
+}
+
+func (l *resourceLedger) take(req models.Request, _ FillMode) (string, int) {
+	if !l.fits(req) {
+		return "", 0
+	}
+	for r, d := range req.Resources {
+		l.remaining[r] -= d
+	}
+	return "", req.Size
+}
+
+func (l *resourceLedger) empty() bool {
+	for _, left := range l.remaining {
+		if left > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *resourceLedger) capacity() map[string]int {
+	return l.total
+}
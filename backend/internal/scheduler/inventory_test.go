@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestResourceLedger(t *testing.T) {
+	l := newResourceLedger(map[string]int{"cpu": 4, "gpu": 1})
+
+	steps := []struct {
+		req   models.Request
+		units int // 0 = rejected
+		empty bool
+	}{
+		{models.Request{ID: 1, Size: 1, Resources: map[string]int{"cpu": 2, "gpu": 1}}, 1, false},
+		// all or nothing over the vector, even with FillPartial
+		{models.Request{ID: 2, Size: 1, Resources: map[string]int{"cpu": 1, "gpu": 1}}, 0, false},
+		{models.Request{ID: 3, Size: 1, Resources: map[string]int{"cpu": 3}}, 0, false},
+		{models.Request{ID: 4, Size: 1, Resources: map[string]int{"cpu": 2}}, 1, true},
+	}
+	for _, s := range steps {
+		if got := l.canTake(s.req, FillPartial); got != (s.units > 0) {
+			t.Errorf("request %d: canTake = %v", s.req.ID, got)
+		}
+		pool, units := l.take(s.req, FillPartial)
+		if pool != "" || units != s.units {
+			t.Errorf("request %d: take = %q %d, want \"\" %d", s.req.ID, pool, units, s.units)
+		}
+		if l.empty() != s.empty {
+			t.Errorf("request %d: empty = %v, want %v", s.req.ID, l.empty(), s.empty)
+		}
+	}
+	if l.capacity()["cpu"] != 4 || l.capacity()["gpu"] != 1 {
+		t.Errorf("capacity changed: %v", l.capacity())
+	}
+}
+
+func TestNewInventory(t *testing.T) {
+	if inv := newInventory(Workload{}); inv != nil {
+		t.Errorf("no capacity: got %T, want nil", inv)
+	}
+	if _, ok := newInventory(Workload{Pools: map[string]int{"gpu": 1}}).(*poolLedger); !ok {
+		t.Error("pools: want a pool ledger")
+	}
+	w := Workload{Pools: map[string]int{"gpu": 1}, Resources: map[string]int{"cpu": 1}}
+	if _, ok := newInventory(w).(*resourceLedger); !ok {
+		t.Error("resources replace pools: want a resource ledger")
+	}
+}
+
+func TestSoldOut(t *testing.T) {
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Size: 1},
+			{ID: 2, Size: 1},
+			{ID: 3, Size: 1},
+			{ID: 4, Size: 1},
+			{ID: 5, Size: 1, ArrivalAt: 5},
+		}
+	}
+
+	tests := []struct {
+		name string
+		w    func(w *Workload)
+		want []string
+	}{
+		{
+			name: "unknown capacity",
+			w:    func(w *Workload) {},
+			want: []string{"0 select 1", "1 select 2", "2 select 3", "3 select 4", "5 select 5"},
+		},
+		{
+			// hết capacity: request còn chờ bị reject một lần, request tới sau bị reject khi tới
+			name: "pool",
+			w:    func(w *Workload) { w.Pools = map[string]int{DefaultPool: 2} },
+			want: []string{"0 select 1", "1 select 2", "1 reject 3", "1 reject 4", "5 reject 5"},
+		},
+		{
+			name: "resources",
+			w: func(w *Workload) {
+				w.Resources = map[string]int{"cpu": 3}
+				for i := range w.Requests {
+					w.Requests[i].Resources = map[string]int{"cpu": 1}
+				}
+			},
+			want: []string{"0 select 1", "1 select 2", "2 select 3", "2 reject 4", "5 reject 5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			tt.w(&w)
+			decisions := NewFIFOStrategy().Schedule(w)
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionReject), tt.want)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import "container/heap"
+
+// KnapsackStrategy serves by Priority like PriorityStrategy, but skips a
+// request that no longer fits the remaining capacity of its pools and serves the best
+// one that does. A large VIP order then cannot block small orders while
+// there are still units left for them.
+// When nothing fits, the head of the queue is returned anyway so runQueue
+// rejects it (or fills it partially) instead of letting it wait forever.
+type KnapsackStrategy struct{}
+
+func NewKnapsackStrategy() *KnapsackStrategy {
+	return &KnapsackStrategy{}
+}
+
+func (s *KnapsackStrategy) Name() string {
+	return "knapsack"
+}
+
+func (s *KnapsackStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, &knapsackQueue{
+		ready:   &priorityHeap{tieBreak: TieBreakArrival},
+		members: newQueueMembership(),
+	})
+}
+
+type knapsackQueue struct {
+	ready   *priorityHeap
+	members queueMembership
+	// inv là capacity còn lại do runQueue cập nhật, nil = không biết
+	inv inventory
+}
+
+func (q *knapsackQueue) useInventory(inv inventory) {
+	q.inv = inv
+}
+
+func (q *knapsackQueue) Push(req runtimeRequest) {
+	heap.Push(q.ready, req)
+	q.members.add(req.ID)
+}
+
+func (q *knapsackQueue) Pop(now int) (runtimeRequest, float64) {
+	selected := q.popFitting()
+	q.members.served(selected.ID)
+
+	return selected, float64(selected.Priority)
+}
+
+// popFitting pops the best queued request that fits one of its pools,
+// or the best one overall when none fits
+func (q *knapsackQueue) popFitting() runtimeRequest {
+	var skipped []runtimeRequest
+	var selected *runtimeRequest
+
+	for q.ready.Len() > 0 {
+		req := heap.Pop(q.ready).(runtimeRequest)
+		if q.members.skip(req.ID) {
+			continue
+		}
+		if q.inv == nil || q.inv.fits(req.Request) {
+			selected = &req
+			break
+		}
+		skipped = append(skipped, req)
+	}
+
+	if selected == nil {
+		// không request nào vừa, phục vụ request đứng đầu
+		head := skipped[0]
+		skipped = skipped[1:]
+		selected = &head
+	}
+	for _, req := range skipped {
+		heap.Push(q.ready, req)
+	}
+
+	return *selected
+}
+
+func (q *knapsackQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, req := range q.ready.items {
+		if q.members.queued[req.ID] {
+			fn(req, float64(req.Priority))
+		}
+	}
+}
+
+func (q *knapsackQueue) Remove(id int) bool {
+	return q.members.remove(id)
+}
+
+func (q *knapsackQueue) Len() int {
+	return q.members.len()
+}
+
+func (q *knapsackQueue) lowerScoreFirst() bool { return true }
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestKnapsackSkipsRequestsThatDoNotFit(t *testing.T) {
+	// 5 units: after request 1 only 1 unit is left, request 2 needs 3
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Priority: 1, Size: 4},
+			{ID: 2, Priority: 1, Size: 3, ArrivalAt: 1},
+			{ID: 3, Priority: 2, Size: 1, ArrivalAt: 1},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		fill     FillMode
+		want     []string
+	}{
+		{
+			name:     "knapsack",
+			strategy: NewKnapsackStrategy(),
+			fill:     FillAllOrNothing,
+			want:     []string{"0 select 1", "1 select 3", "1 reject 2"},
+		},
+		{
+			name:     "priority rejects the head",
+			strategy: NewPriorityStrategy(PriorityConfig{Preemptive: true}),
+			fill:     FillAllOrNothing,
+			want:     []string{"0 select 1", "1 reject 2", "1 select 3"},
+		},
+		{
+			// knapsack still prefers a request it can serve in full
+			name:     "knapsack partial",
+			strategy: NewKnapsackStrategy(),
+			fill:     FillPartial,
+			want:     []string{"0 select 1", "1 select 3", "1 reject 2"},
+		},
+		{
+			name:     "priority partial",
+			strategy: NewPriorityStrategy(PriorityConfig{Preemptive: true}),
+			fill:     FillPartial,
+			want:     []string{"0 select 1", "1 select 2", "1 reject 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			w.Pools = map[string]int{DefaultPool: 5}
+			w.Fill = tt.fill
+			assertTimeline(t, timeline(tt.strategy.Schedule(w), ActionSelect, ActionReject), tt.want)
+		})
+	}
+}
+
+func TestKnapsackWithoutCapacity(t *testing.T) {
+	// unknown capacity: plain priority order
+	w := testWorkload(
+		models.Request{ID: 1, Priority: 3, Size: 100},
+		models.Request{ID: 2, Priority: 1, Size: 100},
+	)
+	assertTimeline(t, timeline(NewKnapsackStrategy().Schedule(w), ActionSelect), []string{"0 select 2", "1 select 1"})
+}
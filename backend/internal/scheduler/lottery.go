@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"math/rand"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// LotteryStrategy gives every queued request tickets equal to its client's
+// Weight and draws one winner per tick.
+// The RNG is derived from Workload.Seed so a run is reproducible.
+type LotteryStrategy struct{}
+
+func NewLotteryStrategy() *LotteryStrategy {
+	return &LotteryStrategy{}
+}
+
+func (s *LotteryStrategy) Name() string {
+	return "lottery"
+}
+
+func (s *LotteryStrategy) Schedule(w Workload) []Decision {
+	return runQueue(w, &lotteryQueue{
+		// seed và seed+1 đã dùng cho GenerateClients / GenerateRequests
+		rng:     rand.New(rand.NewSource(w.Seed + 2)),
+		clients: w.Clients,
+	})
+}
+
+type lotteryQueue struct {
+	rng     *rand.Rand
+	clients map[int]models.Client
+	items   []runtimeRequest
+	tickets float64 // tổng ticket đang có trong queue
+}
+
+func (q *lotteryQueue) ticketsOf(req runtimeRequest) float64 {
+	if c, ok := q.clients[req.ClientID]; ok && c.Weight > 0 {
+		return c.Weight
+	}
+	return 1
+}
+
+func (q *lotteryQueue) Push(req runtimeRequest) {
+	q.items = append(q.items, req)
+	q.tickets += q.ticketsOf(req)
+}
+
+// Pop draws the winner; score is its probability of winning this draw
+func (q *lotteryQueue) Pop(now int) (runtimeRequest, float64) {
+	draw := q.rng.Float64() * q.tickets
+
+	winner := len(q.items) - 1
+	for i, req := range q.items {
+		draw -= q.ticketsOf(req)
+		if draw < 0 {
+			winner = i
+			break
+		}
+	}
+
+	selected := q.items[winner]
+	tickets := q.ticketsOf(selected)
+	score := tickets / q.tickets
+
+	q.items = append(q.items[:winner], q.items[winner+1:]...)
+	q.tickets -= tickets
+	if len(q.items) == 0 {
+		// tránh sai số float tích luỹ
+		q.tickets = 0
+	}
+
+	return selected, score
+}
+
+func (q *lotteryQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, req := range q.items {
+		fn(req, q.ticketsOf(req)/q.tickets)
+	}
+}
+
+func (q *lotteryQueue) Remove(id int) bool {
+	for i, req := range q.items {
+		if req.ID != id {
+			continue
+		}
+		q.items = append(q.items[:i], q.items[i+1:]...)
+		q.tickets -= q.ticketsOf(req)
+		if len(q.items) == 0 {
+			q.tickets = 0
+		}
+		return true
+	}
+	return false
+}
+
+func (q *lotteryQueue) Len() int {
+	return len(q.items)
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestLottery(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[int]float64 // client ID -> weight
+		want    float64         // share of runs request 1 wins the first draw
+	}{
+		{name: "equal", weights: map[int]float64{1: 1, 2: 1}, want: 0.5},
+		{name: "3 to 1", weights: map[int]float64{1: 3, 2: 1}, want: 0.75},
+		// weight 0 vẫn có 1 ticket
+		{name: "zero weight", weights: map[int]float64{1: 0, 2: 3}, want: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const runs = 4000
+			wins := 0
+			for seed := int64(0); seed < runs; seed++ {
+				w := testWorkload(models.Request{ID: 1}, models.Request{ID: 2})
+				w.Seed = seed
+				for id, weight := range tt.weights {
+					w.Clients[id] = models.Client{ID: id, Class: "free", Weight: weight}
+				}
+
+				decisions := NewLotteryStrategy().Schedule(w)
+				var selects []Decision
+				for _, d := range decisions {
+					if d.Action == ActionSelect {
+						selects = append(selects, d)
+					}
+				}
+				if len(selects) != 2 || selects[1].Score != 1 {
+					t.Fatalf("seed %d: selects = %+v", seed, selects)
+				}
+				// score là xác suất thắng lượt rút đó
+				want := tt.want
+				if selects[0].Request.ID == 1 {
+					wins++
+				} else {
+					want = 1 - tt.want
+				}
+				if math.Abs(selects[0].Score-want) > 1e-9 {
+					t.Fatalf("seed %d: first score = %v, want %v", seed, selects[0].Score, want)
+				}
+			}
+			if got := float64(wins) / runs; math.Abs(got-tt.want) > 0.03 {
+				t.Errorf("request 1 won %.3f of the runs, want about %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLotteryDeterministic(t *testing.T) {
+	var requests []models.Request
+	for id := 1; id <= 50; id++ {
+		requests = append(requests, models.Request{ID: id, ArrivalAt: id % 7})
+	}
+	run := func(seed int64) []string {
+		w := testWorkload(append([]models.Request(nil), requests...)...)
+		w.Seed = seed
+		return timeline(NewLotteryStrategy().Schedule(w), ActionSelect)
+	}
+
+	if !reflect.DeepEqual(run(3), run(3)) {
+		t.Error("same seed, different draws")
+	}
+	if reflect.DeepEqual(run(3), run(4)) {
+		t.Error("different seeds, same draws")
+	}
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// DefaultPool is the pool of a simulation that does not declare any
+const DefaultPool = "default"
+
+// poolLedger tracks the units left in each pool as the scheduler hands them out.
+// It mirrors what SlotStore will do with the decisions, so strategies can
+// reason about capacity without talking to redis.
+type poolLedger struct {
+	total     map[string]int
+	remaining map[string]int
+	names     []string // sorted, for requests that accept any pool
+}
+
+func newPoolLedger(pools map[string]int) *poolLedger {
+	l := &poolLedger{
+		total:     make(map[string]int, len(pools)),
+		remaining: make(map[string]int, len(pools)),
+	}
+	for name, slots := range pools {
+		l.total[name] = slots
+		l.remaining[name] = slots
+		l.names = append(l.names, name)
+	}
+	sort.Strings(l.names)
+	return l
+}
+
+// acceptable is the pool preference list of req
+func (l *poolLedger) acceptable(req models.Request) []string {
+	if len(req.Pools) > 0 {
+		return req.Pools
+	}
+	return l.names
+}
+
+// fits reports whether some acceptable pool still holds all of req
+func (l *poolLedger) fits(req models.Request) bool {
+	for _, pool := range l.acceptable(req) {
+		if l.remaining[pool] >= req.Size {
+			return true
+		}
+	}
+	return false
+}
+
+// canTake reports whether take would grant req anything
+func (l *poolLedger) canTake(req models.Request, fill FillMode) bool {
+	if l.fits(req) {
+		return true
+	}
+	if fill != FillPartial {
+		return false
+	}
+	for _, pool := range l.acceptable(req) {
+		if l.remaining[pool] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// take charges req to the first acceptable pool that holds all of it, or with
+// FillPartial to the first one that has anything left.
+// Returns the pool and the units granted, 0 if req is rejected.
+func (l *poolLedger) take(req models.Request, fill FillMode) (string, int) {
+	pools := l.acceptable(req)
+	for _, pool := range pools {
+		if l.remaining[pool] >= req.Size {
+			l.remaining[pool] -= req.Size
+			return pool, req.Size
+		}
+	}
+	if fill != FillPartial {
+		return "", 0
+	}
+	for _, pool := range pools {
+		if left := l.remaining[pool]; left > 0 {
+			l.remaining[pool] = 0
+			return pool, left
+		}
+	}
+	return "", 0
+}
+
+func (l *poolLedger) empty() bool {
+	for _, left := range l.remaining {
+		if left > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *poolLedger) capacity() map[string]int {
+	return l.total
+}
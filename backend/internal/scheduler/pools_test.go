@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestPoolLedgerTake(t *testing.T) {
+	type grant struct {
+		pool  string
+		units int
+	}
+
+	tests := []struct {
+		name     string
+		fill     FillMode
+		requests []models.Request
+		want     []grant
+		empty    bool
+	}{
+		{
+			name: "preferred pool first",
+			fill: FillAllOrNothing,
+			requests: []models.Request{
+				{ID: 1, Size: 2, Pools: []string{"gpu", "cpu"}},
+				{ID: 2, Size: 2, Pools: []string{"gpu", "cpu"}},
+			},
+			want: []grant{{"gpu", 2}, {"cpu", 2}},
+		},
+		{
+			name: "all or nothing rejects",
+			fill: FillAllOrNothing,
+			requests: []models.Request{
+				{ID: 1, Size: 3, Pools: []string{"gpu"}},
+				{ID: 2, Size: 5, Pools: []string{"cpu"}},
+			},
+			want: []grant{{"gpu", 3}, {"", 0}},
+		},
+		{
+			name: "partial takes what is left",
+			fill: FillPartial,
+			requests: []models.Request{
+				{ID: 1, Size: 5, Pools: []string{"gpu", "cpu"}},
+				{ID: 2, Size: 1, Pools: []string{"gpu"}},
+			},
+			want:  []grant{{"gpu", 3}, {"", 0}},
+			empty: false,
+		},
+		{
+			// 5 không vừa pool nào, partial lấy gpu (ưu tiên) rồi cpu
+			name: "partial drains in preference order",
+			fill: FillPartial,
+			requests: []models.Request{
+				{ID: 1, Size: 5, Pools: []string{"gpu", "cpu"}},
+				{ID: 2, Size: 5, Pools: []string{"gpu", "cpu"}},
+			},
+			want:  []grant{{"gpu", 3}, {"cpu", 4}},
+			empty: true,
+		},
+		{
+			name: "any pool in name order",
+			fill: FillAllOrNothing,
+			requests: []models.Request{
+				{ID: 1, Size: 4},
+				{ID: 2, Size: 3},
+			},
+			want:  []grant{{"cpu", 4}, {"gpu", 3}},
+			empty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newPoolLedger(map[string]int{"gpu": 3, "cpu": 4})
+			for i, req := range tt.requests {
+				pool, units := l.take(req, tt.fill)
+				if pool != tt.want[i].pool || units != tt.want[i].units {
+					t.Errorf("request %d: got %q %d, want %q %d", req.ID, pool, units, tt.want[i].pool, tt.want[i].units)
+				}
+			}
+			if l.empty() != tt.empty {
+				t.Errorf("empty = %v, want %v", l.empty(), tt.empty)
+			}
+			if l.capacity()["gpu"] != 3 || l.capacity()["cpu"] != 4 {
+				t.Errorf("capacity changed: %v", l.capacity())
+			}
+		})
+	}
+}
+
+func TestPoolLedgerCanTake(t *testing.T) {
+	l := newPoolLedger(map[string]int{"gpu": 2})
+	req := models.Request{ID: 1, Size: 3, Pools: []string{"gpu"}}
+
+	if l.fits(req) {
+		t.Error("fits: 3 units in a pool of 2")
+	}
+	if l.canTake(req, FillAllOrNothing) {
+		t.Error("canTake all_or_nothing: 3 units in a pool of 2")
+	}
+	if !l.canTake(req, FillPartial) {
+		t.Error("canTake partial: pool has 2 units left")
+	}
+	if l.canTake(models.Request{ID: 2, Size: 1, Pools: []string{"cpu"}}, FillPartial) {
+		t.Error("canTake: unknown pool")
+	}
+}
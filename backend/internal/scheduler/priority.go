@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// TieBreak decides the order of requests sharing the same Priority
+type TieBreak string
+
+const (
+	TieBreakArrival TieBreak = "arrival" // earliest ArrivalAt first
+	TieBreakLatest  TieBreak = "latest"  // latest ArrivalAt first
+)
+
+type PriorityConfig struct {
+	// Preemptive lets a queued request with a strictly higher priority
+	// (lower Priority value) take the server of a running one, which goes
+	// back to the queue with its remaining service.
+	// Non-preemptive never interrupts a running request.
+	Preemptive bool
+	TieBreak   TieBreak
+}
+
+// Validate accepts "" as TieBreakArrival
+func (c PriorityConfig) Validate() error {
+	switch c.TieBreak {
+	case "", TieBreakArrival, TieBreakLatest:
+		return nil
+	}
+	return fmt.Errorf("unknown tie_break %q", c.TieBreak)
+}
+
+// PriorityStrategy always serves the lowest Priority value first (1 = VIP).
+// This is the strict-priority baseline that starves low classes under load.
+type PriorityStrategy struct {
+	cfg PriorityConfig
+}
+
+func NewPriorityStrategy(cfg PriorityConfig) *PriorityStrategy {
+	if cfg.TieBreak == "" {
+		cfg.TieBreak = TieBreakArrival
+	}
+	return &PriorityStrategy{cfg: cfg}
+}
+
+func (s *PriorityStrategy) Name() string {
+	if s.cfg.Preemptive {
+		return "priority"
+	}
+	return "priority_np"
+}
+
+func (s *PriorityStrategy) Schedule(w Workload) []Decision {
+	q := &priorityQueue{
+		ready:   &priorityHeap{tieBreak: s.cfg.TieBreak},
+		members: newQueueMembership(),
+	}
+	if s.cfg.Preemptive {
+		return runQueue(w, &preemptivePriorityQueue{q})
+	}
+	return runQueue(w, q)
+}
+
+type priorityQueue struct {
+	ready   *priorityHeap
+	members queueMembership
+}
+
+func (q *priorityQueue) Push(req runtimeRequest) {
+	heap.Push(q.ready, req)
+	q.members.add(req.ID)
+}
+
+func (q *priorityQueue) Pop(now int) (runtimeRequest, float64) {
+	q.trim()
+	selected := heap.Pop(q.ready).(runtimeRequest)
+	q.members.served(selected.ID)
+
+	return selected, float64(selected.Priority)
+}
+
+// trim drops removed requests from the top of the heap
+func (q *priorityQueue) trim() {
+	for q.ready.Len() > 0 && q.members.skip(q.ready.items[0].ID) {
+		heap.Pop(q.ready)
+	}
+}
+
+func (q *priorityQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, req := range q.ready.items {
+		if q.members.queued[req.ID] {
+			fn(req, float64(req.Priority))
+		}
+	}
+}
+
+func (q *priorityQueue) Remove(id int) bool {
+	return q.members.remove(id)
+}
+
+func (q *priorityQueue) Len() int {
+	return q.members.len()
+}
+
+func (q *priorityQueue) lowerScoreFirst() bool { return true }
+
+// preemptivePriorityQueue is priorityQueue plus preemption
+type preemptivePriorityQueue struct {
+	*priorityQueue
+}
+
+func (q *preemptivePriorityQueue) Peek() (runtimeRequest, bool) {
+	q.trim()
+	if q.ready.Len() == 0 {
+		return runtimeRequest{}, false
+	}
+	return q.ready.items[0], true
+}
+
+func (q *preemptivePriorityQueue) Preempts(queued, running runtimeRequest) bool {
+	return queued.Priority < running.Priority
+}
+
+// priorityHeap implements heap.Interface ordered by Priority then TieBreak
+type priorityHeap struct {
+	items    []runtimeRequest
+	tieBreak TieBreak
+}
+
+func (h *priorityHeap) Len() int { return len(h.items) }
+
+func (h *priorityHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if a.ArrivalAt != b.ArrivalAt {
+		if h.tieBreak == TieBreakLatest {
+			return a.ArrivalAt > b.ArrivalAt
+		}
+		return a.ArrivalAt < b.ArrivalAt
+	}
+	return a.ID < b.ID
+}
+
+func (h *priorityHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *priorityHeap) Push(x any) { h.items = append(h.items, x.(runtimeRequest)) }
+
+func (h *priorityHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestPriorityPreemption(t *testing.T) {
+	// 1 server: request 1 (low) is running when request 2 (VIP) arrives
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Priority: 3, ArrivalAt: 0, ServiceTime: 5},
+			{ID: 2, Priority: 1, ArrivalAt: 2, ServiceTime: 2},
+			{ID: 3, Priority: 3, ArrivalAt: 1, ServiceTime: 1},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      PriorityConfig
+		requests []models.Request // default requests()
+		want     []string
+	}{
+		{
+			name: "preemptive",
+			cfg:  PriorityConfig{Preemptive: true},
+			want: []string{
+				"0 select 1", "2 preempt 1", "2 select 2", "4 complete 2",
+				"4 resume 1", "7 complete 1", "7 select 3", "8 complete 3",
+			},
+		},
+		{
+			name: "preemptive latest first",
+			cfg:  PriorityConfig{Preemptive: true, TieBreak: TieBreakLatest},
+			want: []string{
+				"0 select 1", "2 preempt 1", "2 select 2", "4 complete 2",
+				"4 select 3", "5 complete 3", "5 resume 1", "8 complete 1",
+			},
+		},
+		{
+			name: "non-preemptive",
+			cfg:  PriorityConfig{},
+			want: []string{
+				"0 select 1", "5 complete 1", "5 select 2", "7 complete 2",
+				"7 select 3", "8 complete 3",
+			},
+		},
+		{
+			// the VIP waiting since tick 2 goes before request 2 queued at tick 0
+			name: "non-preemptive serves the best request when the server frees",
+			cfg:  PriorityConfig{},
+			requests: []models.Request{
+				{ID: 1, Priority: 3, ArrivalAt: 0, ServiceTime: 5},
+				{ID: 2, Priority: 3, ArrivalAt: 0},
+				{ID: 3, Priority: 1, ArrivalAt: 2},
+			},
+			want: []string{
+				"0 select 1", "5 complete 1", "5 select 3", "6 complete 3",
+				"6 select 2", "7 complete 2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.requests == nil {
+				tt.requests = requests()
+			}
+			decisions := NewPriorityStrategy(tt.cfg).Schedule(testWorkload(tt.requests...))
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionPreempt, ActionResume, ActionComplete), tt.want)
+		})
+	}
+}
+
+func TestPriorityEqualNeverPreempts(t *testing.T) {
+	requests := []models.Request{
+		{ID: 1, Priority: 2, ArrivalAt: 0, ServiceTime: 3},
+		{ID: 2, Priority: 2, ArrivalAt: 1, ServiceTime: 1},
+	}
+	decisions := NewPriorityStrategy(PriorityConfig{Preemptive: true}).Schedule(testWorkload(requests...))
+	assertTimeline(t, timeline(decisions, ActionSelect, ActionPreempt), []string{"0 select 1", "3 select 2"})
+}
+
+func TestPriorityConfigValidate(t *testing.T) {
+	tests := []struct {
+		tieBreak TieBreak
+		wantErr  bool
+	}{
+		{"", false},
+		{TieBreakArrival, false},
+		{TieBreakLatest, false},
+		{"random", true},
+	}
+	for _, tt := range tests {
+		err := PriorityConfig{TieBreak: tt.tieBreak}.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("tie_break %q: err = %v, wantErr %v", tt.tieBreak, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// RetryPolicy is how the clients of one class retry a rejected, shed or
+// dropped request
+type RetryPolicy struct {
+	MaxAttempts int     // attempts in total including the first, <= 1 = no retry
+	Backoff     int     // ticks before the first retry, doubles after each attempt
+	Jitter      float64 // the delay is spread uniformly by ± Jitter of itself
+}
+
+// RetryPolicies is the retry policy of each class, keyed by class name.
+// Classes that never retry are left out.
+func RetryPolicies(classes []models.ClientClass) map[string]RetryPolicy {
+	policies := map[string]RetryPolicy{}
+	for _, c := range classes {
+		if c.MaxAttempts <= 1 {
+			continue
+		}
+		policies[c.Name] = RetryPolicy{
+			MaxAttempts: c.MaxAttempts,
+			Backoff:     max(c.RetryBackoff, 1),
+			Jitter:      c.RetryJitter,
+		}
+	}
+	return policies
+}
+
+// delay is the ticks between the failure of attempt and the next one, >= 1
+func (p RetryPolicy) delay(attempt int, rng *rand.Rand) int {
+	d := p.Backoff << (attempt - 1)
+	if p.Jitter > 0 {
+		d += int(math.Round(float64(d) * p.Jitter * (2*rng.Float64() - 1)))
+	}
+	return max(d, 1)
+}
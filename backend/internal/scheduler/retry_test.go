@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestRetryPolicies(t *testing.T) {
+	got := RetryPolicies([]models.ClientClass{
+		{Name: "vip", MaxAttempts: 3, RetryBackoff: 4, RetryJitter: 0.5},
+		{Name: "paid", MaxAttempts: 2},
+		{Name: "free", MaxAttempts: 1, RetryBackoff: 9},
+	})
+	want := map[string]RetryPolicy{
+		"vip":  {MaxAttempts: 3, Backoff: 4, Jitter: 0.5},
+		"paid": {MaxAttempts: 2, Backoff: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, Backoff: 2}
+	for attempt, want := range map[int]int{1: 2, 2: 4, 3: 8, 4: 16} {
+		if got := p.delay(attempt, nil); got != want {
+			t.Errorf("delay(%d) = %d, want %d", attempt, got, want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	jittered := RetryPolicy{MaxAttempts: 5, Backoff: 8, Jitter: 0.5}
+	tiny := RetryPolicy{MaxAttempts: 5, Backoff: 1, Jitter: 1}
+	for i := 0; i < 1000; i++ {
+		if d := jittered.delay(1, rng); d < 4 || d > 12 {
+			t.Fatalf("delay = %d, want within 8 ± 4", d)
+		}
+		if d := tiny.delay(1, rng); d < 1 {
+			t.Fatalf("delay = %d, want >= 1", d)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	// queue of 1 and 1 server: request 2 is shed at tick 0 and retries
+	// while request 3 holds the only place in the queue
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, ArrivalAt: 0, ServiceTime: 3},
+			{ID: 2, ArrivalAt: 0, ServiceTime: 1},
+			{ID: 3, ArrivalAt: 1, ServiceTime: 1},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		retry map[string]RetryPolicy
+		want  []string
+	}{
+		{
+			name: "no retry",
+			want: []string{"0 shed 2", "0 select 1", "3 select 3"},
+		},
+		{
+			name:  "one retry",
+			retry: map[string]RetryPolicy{"free": {MaxAttempts: 2, Backoff: 2}},
+			want:  []string{"0 shed 2", "0 select 1", "2 shed 4", "3 select 3"},
+		},
+		{
+			// backoff doubles: attempt 3 comes 4 ticks after attempt 2 failed
+			name:  "backoff",
+			retry: map[string]RetryPolicy{"free": {MaxAttempts: 3, Backoff: 2}},
+			want:  []string{"0 shed 2", "0 select 1", "2 shed 4", "3 select 3", "6 select 5"},
+		},
+		{
+			name:  "other class",
+			retry: map[string]RetryPolicy{"vip": {MaxAttempts: 3, Backoff: 2}},
+			want:  []string{"0 shed 2", "0 select 1", "3 select 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			w.Admission = AdmissionConfig{MaxQueue: 1, Policy: AdmitTailDrop}
+			w.Retry = tt.retry
+			decisions := NewFIFOStrategy().Schedule(w)
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionShed), tt.want)
+
+			for _, d := range decisions {
+				if d.Request.ID <= 3 {
+					continue
+				}
+				if d.Request.RetryOf != 2 || d.Request.Attempt != d.Request.ID-2 || d.Request.ClientID != 2 {
+					t.Errorf("request %d: retry_of = %d, attempt = %d, client = %d", d.Request.ID, d.Request.RetryOf, d.Request.Attempt, d.Request.ClientID)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryKeepsDeadlineWindow(t *testing.T) {
+	// request 2 must start by tick 3 to finish in time but request 1 holds
+	// the server until tick 4; the retry gets the same 3 ticks from its arrival
+	w := testWorkload(
+		models.Request{ID: 1, ServiceTime: 4},
+		models.Request{ID: 2, ServiceTime: 1, Deadline: 3},
+	)
+	w.Retry = map[string]RetryPolicy{"free": {MaxAttempts: 2, Backoff: 10}}
+
+	decisions := NewFIFOStrategy().Schedule(w)
+	assertTimeline(t, timeline(decisions, ActionSelect, ActionDrop), []string{
+		"0 select 1", "4 drop 2", "14 select 3",
+	})
+	for _, d := range decisions {
+		if d.Request.ID == 3 && d.Request.Deadline != 17 {
+			t.Errorf("retry deadline = %d, want 17", d.Request.Deadline)
+		}
+	}
+}
+
+func TestRenege(t *testing.T) {
+	w := testWorkload(
+		models.Request{ID: 1, ServiceTime: 5},
+		models.Request{ID: 2, Patience: 2},
+		models.Request{ID: 3, Patience: 10},
+	)
+	// client bỏ đi không retry
+	w.Retry = map[string]RetryPolicy{"free": {MaxAttempts: 3, Backoff: 1}}
+
+	decisions := NewFIFOStrategy().Schedule(w)
+	assertTimeline(t, timeline(decisions, ActionSelect, ActionRenege), []string{
+		"0 select 1", "2 renege 2", "5 select 3",
+	})
+}
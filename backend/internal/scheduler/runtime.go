@@ -1,21 +1,60 @@
 package scheduler
 
-import "github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
 
 type runtimeRequest struct {
 	models.Request
 	EnqueueTick int
+	Remaining   int // ticks of service still needed
 }
 type HybridConfig struct {
 	Alpha float64 // priority weight
 	Beta  float64 // waiting time weight
 	Gamma float64 // fairness penalty
+
+	// WeightedDebt charges 1/Client.Weight per allocation instead of 1,
+	// so heavier clients accrue debt more slowly
+	WeightedDebt bool
+	DebtDecay    DebtDecay
+	DebtHalfLife float64 // ticks for debt to halve, DebtDecayExponential only
+	DebtWindow   int     // ticks an allocation stays in debt, DebtDecayWindow only
+}
+
+// DebtDecay decides how a client's fairness debt fades over time
+type DebtDecay string
+
+const (
+	DebtDecayNone        DebtDecay = "none"        // debt is never forgiven
+	DebtDecayExponential DebtDecay = "exponential" // debt halves every DebtHalfLife ticks
+	DebtDecayWindow      DebtDecay = "window"      // only allocations of the last DebtWindow ticks count
+)
+
+func (c HybridConfig) Validate() error {
+	switch c.DebtDecay {
+	case "", DebtDecayNone:
+	case DebtDecayExponential:
+		if c.DebtHalfLife <= 0 {
+			return fmt.Errorf("debt_half_life must be > 0 for exponential debt decay")
+		}
+	case DebtDecayWindow:
+		if c.DebtWindow <= 0 {
+			return fmt.Errorf("debt_window must be > 0 for window debt decay")
+		}
+	default:
+		return fmt.Errorf("unknown debt_decay %q", c.DebtDecay)
+	}
+	return nil
 }
 
 func computeScore(
 	req runtimeRequest,
 	now int,
-	clientDebt map[int]float64,
+	debt float64,
 	cfg HybridConfig,
 ) float64 {
 
@@ -23,5 +62,133 @@ func computeScore(
 
 	return float64(req.Priority)*cfg.Alpha +
 		float64(waiting)*cfg.Beta -
-		clientDebt[req.ClientID]*cfg.Gamma
+		debt*cfg.Gamma
+}
+
+// readyQueue is the "pick next" part of a strategy.
+// runQueue owns the clock, the arrivals and the deadlines, the queue only decides order.
+type readyQueue interface {
+	Push(req runtimeRequest)
+	// Pop removes the request served at tick now and returns it with its score
+	Pop(now int) (runtimeRequest, float64)
+	// Remove takes a request out without serving it, false if it is not queued
+	Remove(id int) bool
+	// Scores calls fn for every queued request with its score at tick now
+	Scores(now int, fn func(req runtimeRequest, score float64))
+	Len() int
+}
+
+// preemptingQueue is a readyQueue that may take a server away from a
+// running request (e.g. SRPT). runQueue pushes the preempted request back
+// with its Remaining service.
+type preemptingQueue interface {
+	readyQueue
+	// Peek returns the request Pop would return next, false if the queue is empty
+	Peek() (runtimeRequest, bool)
+	// Preempts reports whether queued should replace running on its server
+	Preempts(queued, running runtimeRequest) bool
+}
+
+// chargingQueue is a readyQueue that keeps per-client state of what was
+// allocated (e.g. fairness debt). runQueue calls charge when req is
+// allocated for the first time, not when Pop returns a request the
+// capacity then rejects, nor when a preempted request resumes.
+// pool and units are what the inventory granted, "" and 0 without one.
+type chargingQueue interface {
+	readyQueue
+	charge(req runtimeRequest, now int, pool string, units int)
+}
+
+// queueMembership lets heap-based queues remove requests lazily:
+// Remove only marks the request, the queue skips it when it surfaces.
+type queueMembership struct {
+	queued  map[int]bool
+	removed map[int]bool
+}
+
+func newQueueMembership() queueMembership {
+	return queueMembership{
+		queued:  map[int]bool{},
+		removed: map[int]bool{},
+	}
+}
+
+func (m *queueMembership) add(id int) {
+	m.queued[id] = true
+}
+
+func (m *queueMembership) remove(id int) bool {
+	if !m.queued[id] {
+		return false
+	}
+	delete(m.queued, id)
+	m.removed[id] = true
+	return true
+}
+
+// served records that id left the queue through Pop
+func (m *queueMembership) served(id int) {
+	delete(m.queued, id)
+}
+
+// skip reports whether id was removed and forgets it
+func (m *queueMembership) skip(id int) bool {
+	if !m.removed[id] {
+		return false
+	}
+	delete(m.removed, id)
+	return true
+}
+
+func (m *queueMembership) len() int {
+	return len(m.queued)
+}
+
+// serviceTime is how many ticks req holds a server, at least 1
+func serviceTime(req models.Request) int {
+	if req.ServiceTime < 1 {
+		return 1
+	}
+	return req.ServiceTime
+}
+
+// runningJob is a request holding a server slot since startTick
+type runningJob struct {
+	req       runtimeRequest // Remaining as of startTick
+	startTick int
+}
+
+func (j *runningJob) finish() int {
+	return j.startTick + j.req.Remaining
+}
+
+// remainingAt is the request with the service still needed at tick now
+func (j *runningJob) remainingAt(now int) runtimeRequest {
+	req := j.req
+	req.Remaining -= now - j.startTick
+	return req
+}
+
+func sortedSlots(running map[int]*runningJob) []int {
+	slots := make([]int, 0, len(running))
+	for slot := range running {
+		slots = append(slots, slot)
+	}
+	sort.Ints(slots)
+	return slots
+}
+
+// sortByArrival returns a copy ordered by ArrivalAt, ties broken by ID
+func sortByArrival(requests []models.Request) []models.Request {
+	sorted := make([]models.Request, len(requests))
+	copy(sorted, requests)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].ArrivalAt != sorted[j].ArrivalAt {
+			return sorted[i].ArrivalAt < sorted[j].ArrivalAt
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
 }
@@ -0,0 +1,124 @@
+package scheduler
+
+import "container/heap"
+
+// SJFStrategy serves the request with the shortest service time first.
+// With Preemptive (SRPT) a queued request whose remaining service is shorter
+// than that of a running request takes its server; the running request goes
+// back to the queue with what it has left.
+type SJFStrategy struct {
+	preemptive bool
+}
+
+func NewSJFStrategy() *SJFStrategy {
+	return &SJFStrategy{}
+}
+
+func NewSRPTStrategy() *SJFStrategy {
+	return &SJFStrategy{preemptive: true}
+}
+
+func (s *SJFStrategy) Name() string {
+	if s.preemptive {
+		return "srpt"
+	}
+	return "sjf"
+}
+
+func (s *SJFStrategy) Schedule(w Workload) []Decision {
+	q := &sjfQueue{ready: &remainingHeap{}, members: newQueueMembership()}
+	if s.preemptive {
+		return runQueue(w, &srptQueue{q})
+	}
+	return runQueue(w, q)
+}
+
+type sjfQueue struct {
+	ready   *remainingHeap
+	members queueMembership
+}
+
+func (q *sjfQueue) Push(req runtimeRequest) {
+	heap.Push(q.ready, req)
+	q.members.add(req.ID)
+}
+
+// Pop returns the shortest job; score is its remaining service
+func (q *sjfQueue) Pop(now int) (runtimeRequest, float64) {
+	q.trim()
+	selected := heap.Pop(q.ready).(runtimeRequest)
+	q.members.served(selected.ID)
+	return selected, float64(selected.Remaining)
+}
+
+// trim drops removed requests from the top of the heap
+func (q *sjfQueue) trim() {
+	for q.ready.Len() > 0 && q.members.skip(q.ready.items[0].ID) {
+		heap.Pop(q.ready)
+	}
+}
+
+func (q *sjfQueue) Scores(now int, fn func(req runtimeRequest, score float64)) {
+	for _, req := range q.ready.items {
+		if q.members.queued[req.ID] {
+			fn(req, float64(req.Remaining))
+		}
+	}
+}
+
+func (q *sjfQueue) Remove(id int) bool {
+	return q.members.remove(id)
+}
+
+func (q *sjfQueue) Len() int {
+	return q.members.len()
+}
+
+func (q *sjfQueue) lowerScoreFirst() bool { return true }
+
+// srptQueue is sjfQueue plus preemption
+type srptQueue struct {
+	*sjfQueue
+}
+
+func (q *srptQueue) Peek() (runtimeRequest, bool) {
+	q.trim()
+	if q.ready.Len() == 0 {
+		return runtimeRequest{}, false
+	}
+	return q.ready.items[0], true
+}
+
+func (q *srptQueue) Preempts(queued, running runtimeRequest) bool {
+	return queued.Remaining < running.Remaining
+}
+
+// remainingHeap is a min-heap by Remaining, then ArrivalAt, then ID
+type remainingHeap struct {
+	items []runtimeRequest
+}
+
+func (h *remainingHeap) Len() int { return len(h.items) }
+
+func (h *remainingHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if a.Remaining != b.Remaining {
+		return a.Remaining < b.Remaining
+	}
+	if a.ArrivalAt != b.ArrivalAt {
+		return a.ArrivalAt < b.ArrivalAt
+	}
+	return a.ID < b.ID
+}
+
+func (h *remainingHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *remainingHeap) Push(x any) { h.items = append(h.items, x.(runtimeRequest)) }
+
+func (h *remainingHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestSJF(t *testing.T) {
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, ArrivalAt: 0, ServiceTime: 6},
+			{ID: 2, ArrivalAt: 1, ServiceTime: 2},
+			{ID: 3, ArrivalAt: 1, ServiceTime: 4},
+			{ID: 4, ArrivalAt: 2, ServiceTime: 1},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		servers  int
+		want     []string
+	}{
+		{
+			name:     "sjf runs to completion",
+			strategy: NewSJFStrategy(),
+			want: []string{
+				"0 select 1", "6 complete 1", "6 select 4", "7 complete 4",
+				"7 select 2", "9 complete 2", "9 select 3", "13 complete 3",
+			},
+		},
+		{
+			// request 4 ties with the 1 tick request 2 has left: no preemption
+			name:     "srpt",
+			strategy: NewSRPTStrategy(),
+			want: []string{
+				"0 select 1", "1 preempt 1", "1 select 2", "3 complete 2",
+				"3 select 4", "4 complete 4", "4 select 3", "8 complete 3",
+				"8 resume 1", "13 complete 1",
+			},
+		},
+		{
+			name:     "srpt on 2 servers",
+			strategy: NewSRPTStrategy(),
+			servers:  2,
+			want: []string{
+				"0 select 1", "1 select 2", "1 preempt 1", "1 select 3",
+				"2 preempt 3", "2 select 4", "3 complete 4", "3 complete 2",
+				"3 resume 3", "3 resume 1", "6 complete 3", "8 complete 1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(requests()...)
+			if tt.servers > 0 {
+				w.Service.Servers = tt.servers
+			}
+			decisions := tt.strategy.Schedule(w)
+			assertTimeline(t, timeline(decisions, ActionSelect, ActionPreempt, ActionResume, ActionComplete), tt.want)
+		})
+	}
+}
+
+func TestSRPTPreemptsLongestRemaining(t *testing.T) {
+	// 2 servers: request 3 takes the server of request 1, which has more left
+	w := testWorkload(
+		models.Request{ID: 1, ArrivalAt: 0, ServiceTime: 5},
+		models.Request{ID: 2, ArrivalAt: 0, ServiceTime: 3},
+		models.Request{ID: 3, ArrivalAt: 1, ServiceTime: 1},
+	)
+	w.Service.Servers = 2
+
+	decisions := NewSRPTStrategy().Schedule(w)
+	assertTimeline(t, timeline(decisions, ActionSelect, ActionPreempt, ActionResume, ActionComplete), []string{
+		"0 select 2", "0 select 1", "1 preempt 1", "1 select 3", "2 complete 3",
+		"2 resume 1", "3 complete 2", "6 complete 1",
+	})
+}
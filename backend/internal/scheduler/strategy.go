@@ -2,17 +2,79 @@ package scheduler
 
 import "github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
 
+// Decision actions, in lifecycle order
+const (
+	ActionEnqueue = "enqueue" // request arrived and joined the queue
+	ActionWait    = "wait"    // periodic snapshot of a request still queued
+	ActionSelect  = "select"  // request was picked for allocation
+	ActionDrop    = "drop"    // request expired in the queue
+	ActionReject  = "reject"  // capacity left cannot serve the request, nothing to acquire
+	ActionShed    = "shed"    // admission control turned the request away, queue full
+	ActionRenege  = "renege"  // client ran out of patience and left the queue
+
+	ActionPreempt  = "preempt"  // running request lost its server and went back to the queue
+	ActionResume   = "resume"   // preempted request got a server again, no new allocation
+	ActionComplete = "complete" // request finished its service and freed the server
+)
+
+// FillMode decides how a request larger than the remaining capacity is served
+type FillMode string
+
+const (
+	FillAllOrNothing FillMode = "all_or_nothing" // the request is rejected
+	FillPartial      FillMode = "partial"        // the request gets what is left
+)
+
 // Decision represents a scheduling decision
 type Decision struct {
 	Tick    int
 	Request models.Request
 	Score   float64
+	Server  int // server that served the request, 0-based
+	Action  string
+}
+
+// Workload is the input of a single scheduling run
+type Workload struct {
+	Requests []models.Request
+	Clients  map[int]models.Client // keyed by Client.ID
+	Seed     int64
+	Service  ServiceConfig
+	// WaitSnapshotEvery emits a wait decision for every queued request
+	// each N ticks, 0 disables snapshots
+	WaitSnapshotEvery int
+	// Pools is the number of units of each pool, nil = unknown
+	Pools map[string]int
+	// Fill applies to every request of the run, the caller allocating the
+	// selected requests must use the same mode
+	Fill FillMode
+	// Resources is the capacity vector of a multi-resource run, nil otherwise
+	Resources map[string]int
+	// Admission bounds the queue, zero value = unbounded
+	Admission AdmissionConfig
+	// Retry is the retry policy per class name, missing classes never retry
+	Retry map[string]RetryPolicy
+}
+
+func NewWorkload(clients []models.Client, requests []models.Request, seed int64) Workload {
+	clientMap := make(map[int]models.Client, len(clients))
+	for _, c := range clients {
+		clientMap[c.ID] = c
+	}
+
+	return Workload{
+		Requests: requests,
+		Clients:  clientMap,
+		Seed:     seed,
+		Service:  DefaultServiceConfig(),
+		Fill:     FillAllOrNothing,
+	}
 }
 
 // Strategy defines the interface for different scheduling algorithms
 type Strategy interface {
 	Name() string
-	Schedule(requests []models.Request) []Decision
+	Schedule(w Workload) []Decision
 }
 
 // StrategyFactory handles the creation/retrieval of strategies
@@ -20,12 +82,34 @@ type StrategyFactory struct {
 	strategies map[string]Strategy
 }
 
-func NewStrategyFactory() *StrategyFactory {
+// StrategyConfig carries the tunable parameters of the registered strategies
+type StrategyConfig struct {
+	Hybrid HybridConfig
+	// TieBreak orders equal priorities in priority and priority_np
+	TieBreak TieBreak
+	// Expr is the compiled formula of the expr strategy, nil leaves it unregistered
+	Expr *Expr
+}
+
+func NewStrategyFactory(cfg StrategyConfig) *StrategyFactory {
 	f := &StrategyFactory{
 		strategies: make(map[string]Strategy),
 	}
 	// Register default strategies
-	f.Register(NewHybridStrategy())
+	f.Register(NewFIFOStrategy())
+	f.Register(NewPriorityStrategy(PriorityConfig{Preemptive: true, TieBreak: cfg.TieBreak}))
+	f.Register(NewPriorityStrategy(PriorityConfig{Preemptive: false, TieBreak: cfg.TieBreak}))
+	f.Register(NewLotteryStrategy())
+	f.Register(NewHybridStrategy(cfg.Hybrid))
+	f.Register(NewKnapsackStrategy())
+	f.Register(NewDRFStrategy())
+	f.Register(NewEDFStrategy())
+	f.Register(NewLLFStrategy())
+	f.Register(NewSJFStrategy())
+	f.Register(NewSRPTStrategy())
+	if cfg.Expr != nil {
+		f.Register(NewExprStrategy(cfg.Expr))
+	}
 	return f
 }
 
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestStrategyFactory(t *testing.T) {
+	expr, err := CompileExpr("priority")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewStrategyFactory(StrategyConfig{Expr: expr})
+
+	// các policy mà API chấp nhận
+	for _, name := range []string{"fifo", "priority", "priority_np", "lottery", "hybrid", "knapsack", "drf", "expr", "edf", "llf", "sjf", "srpt"} {
+		s := f.Get(name)
+		if s == nil {
+			t.Errorf("%s is not registered", name)
+			continue
+		}
+		if s.Name() != name {
+			t.Errorf("Get(%q).Name() = %q", name, s.Name())
+		}
+	}
+
+	if f.Get("round_robin") != nil {
+		t.Error("unknown policy must return nil")
+	}
+	if NewStrategyFactory(StrategyConfig{}).Get("expr") != nil {
+		t.Error("expr without a formula must not be registered")
+	}
+}
+
+func TestStrategyFactoryTieBreak(t *testing.T) {
+	// 2 and 3 both wait for the server request 1 holds until tick 3
+	requests := func() []models.Request {
+		return []models.Request{
+			{ID: 1, Priority: 2, ArrivalAt: 0, ServiceTime: 3},
+			{ID: 2, Priority: 2, ArrivalAt: 1},
+			{ID: 3, Priority: 2, ArrivalAt: 2},
+		}
+	}
+
+	tests := []struct {
+		tieBreak TieBreak
+		want     []string
+	}{
+		{TieBreakArrival, []string{"0 select 1", "3 select 2", "4 select 3"}},
+		{TieBreakLatest, []string{"0 select 1", "3 select 3", "4 select 2"}},
+	}
+
+	for _, tt := range tests {
+		for _, policy := range []string{"priority", "priority_np"} {
+			t.Run(policy+" "+string(tt.tieBreak), func(t *testing.T) {
+				s := NewStrategyFactory(StrategyConfig{TieBreak: tt.tieBreak}).Get(policy)
+				assertTimeline(t, timeline(s.Schedule(testWorkload(requests()...)), ActionSelect), tt.want)
+			})
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// MaxTraceRecords bounds the size of an uploaded trace
+const MaxTraceRecords = 500_000
+
+type TraceFormat string
+
+const (
+	TraceCSV    TraceFormat = "csv"
+	TraceNDJSON TraceFormat = "ndjson"
+)
+
+// TraceRecord is one request of a recorded workload.
+// Either ArrivalTick or Timestamp is set, never both kinds in one trace.
+type TraceRecord struct {
+	Line        int        `json:"-"`
+	ClientID    int        `json:"client_id"`
+	Class       string     `json:"class"`
+	ArrivalTick *int       `json:"arrival_tick,omitempty"`
+	Timestamp   *time.Time `json:"timestamp,omitempty"`
+	Size        int        `json:"size,omitempty"`         // requested units, 1 when missing
+	ServiceTime int        `json:"service_time,omitempty"` // ticks holding a server, 1 when missing
+}
+
+// ParseTrace reads a CSV (with header) or NDJSON trace.
+// Columns/keys: client_id, class, arrival_tick or timestamp, optional size and service_time.
+// Timestamps are RFC3339 or unix epoch milliseconds.
+func ParseTrace(r io.Reader, format TraceFormat) ([]TraceRecord, error) {
+	switch format {
+	case TraceCSV:
+		return parseCSVTrace(r)
+	case TraceNDJSON:
+		return parseNDJSONTrace(r)
+	}
+	return nil, fmt.Errorf("unknown trace format %q", format)
+}
+
+func parseCSVTrace(r io.Reader) ([]TraceRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("trace header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"client_id", "class"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("trace header: missing column %q", required)
+		}
+	}
+	_, hasTick := columns["arrival_tick"]
+	_, hasTimestamp := columns["timestamp"]
+	if !hasTick && !hasTimestamp {
+		return nil, fmt.Errorf("trace header: need an arrival_tick or timestamp column")
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []TraceRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(records) >= MaxTraceRecords {
+			return nil, fmt.Errorf("trace has more than %d records", MaxTraceRecords)
+		}
+
+		rec := TraceRecord{Line: line, Class: field(row, "class")}
+
+		if rec.ClientID, err = strconv.Atoi(field(row, "client_id")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid client_id", line)
+		}
+		if v := field(row, "arrival_tick"); v != "" {
+			tick, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid arrival_tick", line)
+			}
+			rec.ArrivalTick = &tick
+		}
+		if v := field(row, "timestamp"); v != "" {
+			ts, err := parseTimestamp(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rec.Timestamp = &ts
+		}
+		if v := field(row, "size"); v != "" {
+			if rec.Size, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid size", line)
+			}
+		}
+		if v := field(row, "service_time"); v != "" {
+			if rec.ServiceTime, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid service_time", line)
+			}
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+func parseNDJSONTrace(r io.Reader) ([]TraceRecord, error) {
+	// timestamp có thể là string RFC3339 hoặc số (epoch ms)
+	type rawRecord struct {
+		ClientID    int             `json:"client_id"`
+		Class       string          `json:"class"`
+		ArrivalTick *int            `json:"arrival_tick"`
+		Timestamp   json.RawMessage `json:"timestamp"`
+		Size        int             `json:"size"`
+		ServiceTime int             `json:"service_time"`
+	}
+
+	var records []TraceRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if len(records) >= MaxTraceRecords {
+			return nil, fmt.Errorf("trace has more than %d records", MaxTraceRecords)
+		}
+
+		var raw rawRecord
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rec := TraceRecord{
+			Line:        line,
+			ClientID:    raw.ClientID,
+			Class:       raw.Class,
+			ArrivalTick: raw.ArrivalTick,
+			Size:        raw.Size,
+			ServiceTime: raw.ServiceTime,
+		}
+		if len(raw.Timestamp) > 0 && string(raw.Timestamp) != "null" {
+			ts, err := parseTimestamp(strings.Trim(string(raw.Timestamp), `"`))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rec.Timestamp = &ts
+		}
+
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func parseTimestamp(v string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	ts, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q (want RFC3339 or epoch ms)", v)
+	}
+	return ts, nil
+}
+
+// TraceWorkload validates a parsed trace against the class catalog and turns
+// it into clients and requests. Timestamps are mapped to ticks of tickDuration
+// counted from the earliest record.
+func TraceWorkload(
+	records []TraceRecord,
+	classes []models.ClientClass,
+	tickDuration time.Duration,
+) ([]models.Client, []models.Request, []models.ClientArrival, error) {
+
+	if len(records) == 0 {
+		return nil, nil, nil, fmt.Errorf("trace is empty")
+	}
+	if tickDuration <= 0 {
+		return nil, nil, nil, fmt.Errorf("tick duration must be > 0")
+	}
+
+	classByName := make(map[string]models.ClientClass, len(classes))
+	for _, c := range classes {
+		classByName[c.Name] = c
+	}
+
+	useTimestamps := records[0].Timestamp != nil && records[0].ArrivalTick == nil
+	var origin time.Time
+	if useTimestamps {
+		origin = *records[0].Timestamp
+	}
+
+	clientByID := map[int]models.Client{}
+	for _, rec := range records {
+		class, ok := classByName[rec.Class]
+		switch {
+		case rec.ClientID <= 0:
+			return nil, nil, nil, fmt.Errorf("line %d: client_id must be > 0", rec.Line)
+		case !ok:
+			return nil, nil, nil, fmt.Errorf("line %d: unknown class %q", rec.Line, rec.Class)
+		case rec.Size < 0:
+			return nil, nil, nil, fmt.Errorf("line %d: size must be >= 1", rec.Line)
+		case rec.ServiceTime < 0:
+			return nil, nil, nil, fmt.Errorf("line %d: service_time must be >= 1", rec.Line)
+		case useTimestamps && rec.Timestamp == nil, !useTimestamps && rec.ArrivalTick == nil:
+			return nil, nil, nil, fmt.Errorf("line %d: mix of arrival_tick and timestamp records", rec.Line)
+		case !useTimestamps && *rec.ArrivalTick < 0:
+			return nil, nil, nil, fmt.Errorf("line %d: arrival_tick must be >= 0", rec.Line)
+		}
+
+		if c, seen := clientByID[rec.ClientID]; seen && c.Class != rec.Class {
+			return nil, nil, nil, fmt.Errorf("line %d: client %d changes class from %s to %s", rec.Line, rec.ClientID, c.Class, rec.Class)
+		}
+		clientByID[rec.ClientID] = models.Client{ID: rec.ClientID, Class: class.Name, Weight: class.Weight}
+
+		if useTimestamps && rec.Timestamp.Before(origin) {
+			origin = *rec.Timestamp
+		}
+	}
+
+	requests := make([]models.Request, 0, len(records))
+	for i, rec := range records {
+		var tick int
+		if useTimestamps {
+			tick = int(rec.Timestamp.Sub(origin) / tickDuration)
+		} else {
+			tick = *rec.ArrivalTick
+		}
+		if tick > maxArrivalTick {
+			return nil, nil, nil, fmt.Errorf("line %d: arrival is more than %d ticks after the first record", rec.Line, maxArrivalTick)
+		}
+
+		class := classByName[rec.Class]
+		req := models.Request{
+			ID:        i + 1,
+			ClientID:  rec.ClientID,
+			Priority:  class.Priority,
+			ArrivalAt: tick,
+			Size:      rec.Size,
+			Pools:     class.Pools,
+			Resources: class.Demand,
+		}
+		if req.Size == 0 {
+			req.Size = 1
+		}
+		req.ServiceTime = rec.ServiceTime
+		if req.ServiceTime == 0 {
+			req.ServiceTime = 1
+		}
+		if class.TTL > 0 {
+			req.Deadline = req.ArrivalAt + class.TTL
+		}
+		req.Patience, _ = patienceRange(class) // trace không có rng, dùng cận dưới
+		requests = append(requests, req)
+	}
+
+	sortRequests(requests)
+
+	clients := make([]models.Client, 0, len(clientByID))
+	for _, c := range clientByID {
+		clients = append(clients, c)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+
+	return clients, requests, buildArrivalOrder(clients, requests), nil
+}
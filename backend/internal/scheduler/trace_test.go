@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+var traceClasses = []models.ClientClass{
+	{Name: "premium", Priority: 1, Weight: 3, TTL: 5},
+	{Name: "free", Priority: 3, Weight: 1},
+}
+
+func TestTraceServiceTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  TraceFormat
+		trace   string
+		want    []int // ServiceTime theo thứ tự request
+		wantErr string
+	}{
+		{
+			name:   "csv",
+			format: TraceCSV,
+			trace:  "client_id,class,arrival_tick,service_time\n1,premium,0,4\n2,free,1,\n",
+			want:   []int{4, 1},
+		},
+		{
+			name:   "ndjson",
+			format: TraceNDJSON,
+			trace: `{"client_id":1,"class":"premium","arrival_tick":0,"service_time":4}
+{"client_id":2,"class":"free","arrival_tick":1}
+`,
+			want: []int{4, 1},
+		},
+		{
+			name:    "csv negative",
+			format:  TraceCSV,
+			trace:   "client_id,class,arrival_tick,service_time\n1,premium,0,-2\n",
+			wantErr: "line 2: service_time must be >= 1",
+		},
+		{
+			name:    "ndjson negative",
+			format:  TraceNDJSON,
+			trace:   `{"client_id":1,"class":"premium","arrival_tick":0,"service_time":-2}` + "\n",
+			wantErr: "line 1: service_time must be >= 1",
+		},
+		{
+			name:    "ndjson wrong type",
+			format:  TraceNDJSON,
+			trace:   `{"client_id":1,"class":"premium","arrival_tick":0,"service_time":"4"}` + "\n",
+			wantErr: "line 1:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requests, _, err := parseAndBuild(tt.trace, tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(requests) != len(tt.want) {
+				t.Fatalf("got %d requests, want %d", len(requests), len(tt.want))
+			}
+			for i, req := range requests {
+				if req.ServiceTime != tt.want[i] {
+					t.Errorf("request %d: service_time = %d, want %d", req.ID, req.ServiceTime, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTraceWorkload(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  TraceFormat
+		trace   string
+		arrival []int
+		wantErr string
+	}{
+		{
+			name:    "ticks sorted",
+			format:  TraceCSV,
+			trace:   "client_id,class,arrival_tick\n1,premium,3\n2,free,1\n1,premium,1\n",
+			arrival: []int{1, 1, 3},
+		},
+		{
+			name:    "timestamps from earliest record",
+			format:  TraceNDJSON,
+			trace:   `{"client_id":1,"class":"premium","timestamp":1000}` + "\n" + `{"client_id":2,"class":"free","timestamp":"1970-01-01T00:00:00.500Z"}` + "\n",
+			arrival: []int{0, 5},
+		},
+		{
+			name:    "unknown class",
+			format:  TraceCSV,
+			trace:   "client_id,class,arrival_tick\n1,gold,0\n",
+			wantErr: `line 2: unknown class "gold"`,
+		},
+		{
+			name:    "client changes class",
+			format:  TraceCSV,
+			trace:   "client_id,class,arrival_tick\n1,premium,0\n1,free,1\n",
+			wantErr: "client 1 changes class",
+		},
+		{
+			name:    "mixed arrival kinds",
+			format:  TraceNDJSON,
+			trace:   `{"client_id":1,"class":"premium","arrival_tick":0}` + "\n" + `{"client_id":2,"class":"free","timestamp":1000}` + "\n",
+			wantErr: "mix of arrival_tick and timestamp",
+		},
+		{
+			name:    "empty",
+			format:  TraceCSV,
+			trace:   "client_id,class,arrival_tick\n",
+			wantErr: "trace is empty",
+		},
+		{
+			name:    "negative tick",
+			format:  TraceCSV,
+			trace:   "client_id,class,arrival_tick\n1,free,-1\n",
+			wantErr: "line 2: arrival_tick must be >= 0",
+		},
+		{
+			name:    "missing column",
+			format:  TraceCSV,
+			trace:   "client_id,arrival_tick\n1,0\n",
+			wantErr: `missing column "class"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requests, _, err := parseAndBuild(tt.trace, tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, req := range requests {
+				if req.ArrivalAt != tt.arrival[i] {
+					t.Errorf("request %d: arrival = %d, want %d", req.ID, req.ArrivalAt, tt.arrival[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTraceWorkloadClasses(t *testing.T) {
+	trace := "client_id,class,arrival_tick,size\n7,premium,2,3\n9,free,0,\n7,premium,4,\n"
+	clients, requests, _, err := parseAndBuild(trace, TraceCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clients) != 2 {
+		t.Fatalf("got %d clients, want 2", len(clients))
+	}
+	for _, c := range clients {
+		if c.ID == 7 && (c.Class != "premium" || c.Weight != 3) {
+			t.Errorf("client 7 = %+v, want premium weight 3", c)
+		}
+	}
+
+	// ID theo thứ tự dòng, request sắp theo arrival; priority và deadline
+	// (arrival + TTL) lấy từ class, size từ trace
+	want := []models.Request{
+		{ID: 2, ClientID: 9, Priority: 3, ArrivalAt: 0, Size: 1},
+		{ID: 1, ClientID: 7, Priority: 1, ArrivalAt: 2, Deadline: 7, Size: 3},
+		{ID: 3, ClientID: 7, Priority: 1, ArrivalAt: 4, Deadline: 9, Size: 1},
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(requests), len(want))
+	}
+	for i, w := range want {
+		req := requests[i]
+		if req.ID != w.ID || req.ClientID != w.ClientID || req.Priority != w.Priority ||
+			req.ArrivalAt != w.ArrivalAt || req.Deadline != w.Deadline || req.Size != w.Size {
+			t.Errorf("requests[%d] = %+v, want %+v", i, req, w)
+		}
+	}
+}
+
+func parseAndBuild(trace string, format TraceFormat) ([]models.Client, []models.Request, []models.ClientArrival, error) {
+	records, err := ParseTrace(strings.NewReader(trace), format)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return TraceWorkload(records, traceClasses, 100*time.Millisecond)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+// Compare generates one workload and runs every requested policy on it.
+// Each run gets its own simulation ID, so the slot counters never mix.
+func (s *SimulateService) Compare(
+	input models.CompareRequest,
+) (*models.CompareResponse, error) {
+
+	settings := make([]runSettings, 0, len(input.Policies))
+	for _, policy := range input.Policies {
+		st, err := s.runSettings(input.SimulationWorkload, policy, input.Hybrid, input.Expr)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, st)
+	}
+
+	// classes không phụ thuộc policy, sinh workload 1 lần
+	classes := settings[0].classes
+	run, err := generateWorkload(input.SimulationWorkload, classes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.CompareResponse{
+		Seed:          input.Seed,
+		TotalRequests: len(run.requests),
+		Classes:       classes,
+	}
+	for _, st := range settings {
+		result, err := s.execute(st, run)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Results = append(resp.Results, models.CompareResult{
+			Policy:       result.Simulation.Policy,
+			SimulationID: result.Simulation.ID,
+			Metrics:      result.Metrics,
+		})
+	}
+
+	return resp, nil
+}
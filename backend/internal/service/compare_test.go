@@ -0,0 +1,108 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestCompare(t *testing.T) {
+	s, store := newTestService()
+	input := models.CompareRequest{
+		SimulationWorkload: models.SimulationWorkload{TotalClients: 40, TotalVouchers: 10, Seed: 3},
+		Policies:           []string{"fifo", "priority", "priority_np", "lottery", "hybrid", "knapsack", "expr", "edf", "llf", "sjf", "srpt"},
+	}
+
+	resp, err := s.Compare(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != len(input.Policies) {
+		t.Fatalf("got %d results, want %d", len(resp.Results), len(input.Policies))
+	}
+
+	ids := map[string]bool{}
+	for i, r := range resp.Results {
+		if r.Policy != input.Policies[i] {
+			t.Errorf("result %d: policy = %q, want %q", i, r.Policy, input.Policies[i])
+		}
+		ids[r.SimulationID] = true
+
+		requests, units := 0, 0
+		for _, c := range r.Metrics.Classes {
+			requests += c.Requests
+			units += c.Units
+		}
+		// cùng 1 workload, 10 voucher đều được phát hết
+		if requests != resp.TotalRequests || units != 10 {
+			t.Errorf("%s: %d requests and %d units, want %d and 10", r.Policy, requests, units, resp.TotalRequests)
+		}
+	}
+	if len(ids) != len(input.Policies) {
+		t.Errorf("simulation IDs are not unique: %v", ids)
+	}
+	if len(store.left) != 0 {
+		t.Errorf("slot counters left behind: %v", store.left)
+	}
+
+	// mỗi policy trong Compare giống hệt 1 lần chạy riêng
+	for _, r := range resp.Results {
+		single, err := s.RunSimulation(models.SimulationRequest{SimulationWorkload: input.SimulationWorkload, Policy: r.Policy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(r.Metrics, single.Metrics) {
+			t.Errorf("%s: compare metrics differ from a single run\n got: %+v\nwant: %+v", r.Policy, r.Metrics, single.Metrics)
+		}
+	}
+}
+
+func TestCompareInvalidPolicySettings(t *testing.T) {
+	s, store := newTestService()
+	_, err := s.Compare(models.CompareRequest{
+		SimulationWorkload: models.SimulationWorkload{TotalClients: 5, TotalVouchers: 2},
+		Policies:           []string{"fifo", "expr"},
+		Expr:               "wait +",
+	})
+	checkErr(t, err, "expr:")
+	if len(store.left) != 0 {
+		t.Errorf("nothing should run when a policy is invalid: %v", store.left)
+	}
+}
+
+func TestCompareResources(t *testing.T) {
+	s, store := newTestService()
+	input := models.CompareRequest{
+		SimulationWorkload: models.SimulationWorkload{
+			TotalClients: 20,
+			Seed:         5,
+			Resources:    map[string]int{"cpu": 8, "gpu": 2},
+			Classes: []models.ClientClass{
+				{Name: "train", Share: 1, Weight: 1, Priority: 1, MinRequests: 1, MaxRequests: 2, Demand: map[string]int{"cpu": 1, "gpu": 1}},
+				{Name: "batch", Share: 2, Weight: 1, Priority: 2, MinRequests: 1, MaxRequests: 2, Demand: map[string]int{"cpu": 2}},
+			},
+		},
+		Policies: []string{"drf", "fifo", "priority"},
+	}
+
+	resp, err := s.Compare(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range resp.Results {
+		if r.Policy != input.Policies[i] {
+			t.Errorf("result %d: policy = %q, want %q", i, r.Policy, input.Policies[i])
+		}
+		allocated := 0
+		for _, c := range r.Metrics.Classes {
+			allocated += c.Allocated
+		}
+		if allocated == 0 {
+			t.Errorf("%s: nothing allocated", r.Policy)
+		}
+	}
+	if len(store.left) != 0 {
+		t.Errorf("resource counters left behind: %v", store.left)
+	}
+}
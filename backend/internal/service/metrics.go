@@ -0,0 +1,209 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/scheduler"
+)
+
+// DefaultStarvationThreshold is the wait in ticks above which a request is starved
+const DefaultStarvationThreshold = 100
+
+// computeMetrics derives the per-class report of a run from its events
+func computeMetrics(
+	settings runSettings,
+	run runWorkload,
+	events []models.Event,
+) models.Metrics {
+
+	starvationThreshold := settings.starvationThreshold
+	if starvationThreshold <= 0 {
+		starvationThreshold = DefaultStarvationThreshold
+	}
+
+	clientByID := make(map[int]models.Client, len(run.clients))
+	for _, c := range run.clients {
+		clientByID[c.ID] = c
+	}
+	requestByID := make(map[int]models.Request, len(run.requests))
+	classes := map[string]models.ClassMetrics{}
+	for _, c := range settings.classes {
+		classes[c.Name] = models.ClassMetrics{}
+	}
+	for _, r := range run.requests {
+		requestByID[r.ID] = r
+		class := clientByID[r.ClientID].Class
+		m := classes[class]
+		m.Requests++
+		classes[class] = m
+	}
+	for _, r := range run.retries {
+		requestByID[r.ID] = r
+		class := clientByID[r.ClientID].Class
+		m := classes[class]
+		m.Retries++
+		classes[class] = m
+	}
+
+	withDeadline := map[string]int{}
+	for _, r := range run.requests {
+		if r.Deadline > 0 {
+			withDeadline[clientByID[r.ClientID].Class]++
+		}
+	}
+	metRoots := map[int]bool{} // original request ID -> một attempt đã xong trước deadline
+
+	allocatedIDs := map[int]bool{}
+	response := map[string]float64{} // class -> sum of response times
+	slowdown := map[string]float64{}
+	completed := map[string]int{}
+
+	waits := map[string][]int{}
+	demand := map[string]map[string]int{} // class -> allocated resource vector
+	allocated := map[int]float64{}        // client ID -> units
+	starved, maxWait := 0, 0
+	for _, e := range events {
+		switch e.Action {
+		case models.EventAllocated, models.EventRejected, models.EventDrop, models.EventShed, models.EventReneged:
+		case models.EventCompleted:
+			// chỉ tính request đã allocated, rejected thì không được phục vụ
+			if allocatedIDs[e.RequestID] {
+				req := requestByID[e.RequestID]
+				class := clientByID[e.ClientID].Class
+				r := float64(e.Tick - req.ArrivalAt)
+				response[class] += r
+				slowdown[class] += r / float64(max(req.ServiceTime, 1))
+				completed[class]++
+
+				// tick cuối được phục vụ là e.Tick-1
+				if req.Deadline > 0 && e.Tick-1 <= req.Deadline {
+					root := e.RequestID
+					if req.RetryOf != 0 {
+						root = req.RetryOf
+					}
+					metRoots[root] = true
+				}
+			}
+			continue
+		default:
+			continue
+		}
+
+		req := requestByID[e.RequestID]
+		wait := e.Tick - req.ArrivalAt
+		if wait > starvationThreshold {
+			starved++
+		}
+		if wait > maxWait {
+			maxWait = wait
+		}
+
+		class := clientByID[e.ClientID].Class
+		m := classes[class]
+		switch e.Action {
+		case models.EventRejected:
+			m.Rejected++
+		case models.EventDrop:
+			m.Dropped++
+		case models.EventShed:
+			m.Shed++
+		case models.EventReneged:
+			m.Reneged++
+		case models.EventAllocated:
+			allocatedIDs[e.RequestID] = true
+			m.Allocated++
+			m.Units += e.Granted
+			waits[class] = append(waits[class], wait)
+			allocated[e.ClientID] += float64(e.Granted)
+
+			if demand[class] == nil {
+				demand[class] = map[string]int{}
+			}
+			for r, d := range req.Resources {
+				demand[class][r] += d
+			}
+		}
+		classes[class] = m
+	}
+
+	missed := map[string]int{}
+	for _, r := range run.requests {
+		if r.Deadline > 0 && !metRoots[r.ID] {
+			missed[clientByID[r.ClientID].Class]++
+		}
+	}
+
+	for class, m := range classes {
+		if w := waits[class]; len(w) > 0 {
+			sort.Ints(w)
+			m.WaitP50 = percentile(w, 50)
+			m.WaitP95 = percentile(w, 95)
+			m.WaitP99 = percentile(w, 99)
+		}
+
+		if n := completed[class]; n > 0 {
+			m.MeanResponse = response[class] / float64(n)
+			m.MeanSlowdown = slowdown[class] / float64(n)
+		}
+		if n := withDeadline[class]; n > 0 {
+			m.DeadlineMissRatio = float64(missed[class]) / float64(n)
+		}
+
+		switch {
+		case len(settings.resources) > 0:
+			m.CapacityShare = scheduler.DominantShare(demand[class], settings.resources)
+		case settings.vouchers > 0:
+			m.CapacityShare = float64(m.Units) / float64(settings.vouchers)
+		}
+		classes[class] = m
+	}
+
+	attempts := len(run.requests) + len(run.retries)
+	amplification := 1.0
+	if len(run.requests) > 0 {
+		amplification = float64(attempts) / float64(len(run.requests))
+	}
+
+	return models.Metrics{
+		Classes:             classes,
+		FairnessIndex:       jainIndex(run.clients, allocated),
+		StarvationThreshold: starvationThreshold,
+		Starved:             starved,
+		MaxWait:             maxWait,
+		Attempts:            attempts,
+		Retries:             len(run.retries),
+		LoadAmplification:   amplification,
+	}
+}
+
+// percentile is the nearest-rank p-th percentile of sorted values
+func percentile(sorted []int, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(sorted[rank-1])
+}
+
+// jainIndex is (Σx)² / (n·Σx²) with x = allocated units / client weight,
+// over every client of the workload
+func jainIndex(clients []models.Client, allocated map[int]float64) float64 {
+	var sum, sumSquares float64
+	for _, c := range clients {
+		x := allocated[c.ID]
+		if c.Weight > 0 {
+			x /= c.Weight
+		}
+		sum += x
+		sumSquares += x * x
+	}
+	if sumSquares == 0 {
+		return 1 // không ai được gì, vẫn là chia đều
+	}
+	return sum * sum / (float64(len(clients)) * sumSquares)
+}
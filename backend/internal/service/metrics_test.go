@@ -0,0 +1,227 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestDeadlineMissRatio(t *testing.T) {
+	clients := []models.Client{{ID: 1, Class: "a", Weight: 1}}
+	request := func(id, deadline, retryOf int) models.Request {
+		return models.Request{ID: id, ClientID: 1, Deadline: deadline, RetryOf: retryOf, Size: 1, ServiceTime: 2}
+	}
+	event := func(tick, id int, action string) models.Event {
+		return models.Event{Tick: tick, RequestID: id, ClientID: 1, Action: action, Size: 1, Granted: 1}
+	}
+
+	tests := []struct {
+		name     string
+		requests []models.Request
+		retries  []models.Request
+		events   []models.Event
+		want     float64
+	}{
+		{
+			// served in ticks 4-5, last tick is the deadline
+			name:     "completed on time",
+			requests: []models.Request{request(1, 5, 0)},
+			events:   []models.Event{event(4, 1, models.EventAllocated), event(6, 1, models.EventCompleted)},
+			want:     0,
+		},
+		{
+			name:     "completed late",
+			requests: []models.Request{request(1, 5, 0)},
+			events:   []models.Event{event(5, 1, models.EventAllocated), event(7, 1, models.EventCompleted)},
+			want:     1,
+		},
+		{
+			name:     "allocated but never completed",
+			requests: []models.Request{request(1, 5, 0)},
+			events:   []models.Event{event(4, 1, models.EventAllocated)},
+			want:     1,
+		},
+		{
+			name:     "dropped",
+			requests: []models.Request{request(1, 5, 0), request(2, 5, 0)},
+			events: []models.Event{
+				event(0, 1, models.EventAllocated), event(2, 1, models.EventCompleted),
+				event(5, 2, models.EventDrop),
+			},
+			want: 0.5,
+		},
+		{
+			name:     "retry meets its own deadline",
+			requests: []models.Request{request(1, 5, 0)},
+			retries:  []models.Request{request(2, 9, 1)},
+			events: []models.Event{
+				event(0, 1, models.EventRejected),
+				event(6, 2, models.EventAllocated), event(8, 2, models.EventCompleted),
+			},
+			want: 0,
+		},
+		{
+			name:     "no deadline",
+			requests: []models.Request{request(1, 0, 0)},
+			events:   []models.Event{event(50, 1, models.EventAllocated), event(52, 1, models.EventCompleted)},
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := runSettings{classes: []models.ClientClass{{Name: "a", Weight: 1}}, vouchers: 10}
+			run := runWorkload{clients: clients, requests: tt.requests, retries: tt.retries}
+
+			got := computeMetrics(settings, run, tt.events).Classes["a"].DeadlineMissRatio
+			if got != tt.want {
+				t.Errorf("deadline_miss_ratio = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeMetrics(t *testing.T) {
+	clients := []models.Client{
+		{ID: 1, Class: "vip", Weight: 2},
+		{ID: 2, Class: "free", Weight: 1},
+	}
+	requests := []models.Request{
+		{ID: 1, ClientID: 1, ArrivalAt: 0, Size: 2, ServiceTime: 1},
+		{ID: 2, ClientID: 2, ArrivalAt: 0, Size: 1, ServiceTime: 2},
+		{ID: 3, ClientID: 2, ArrivalAt: 1, Size: 1, ServiceTime: 1},
+		{ID: 4, ClientID: 2, ArrivalAt: 1, Size: 1, ServiceTime: 1},
+	}
+	retries := []models.Request{{ID: 5, ClientID: 2, ArrivalAt: 3, Size: 1, ServiceTime: 1, RetryOf: 4, Attempt: 2}}
+	events := []models.Event{
+		{Tick: 0, RequestID: 1, ClientID: 1, Action: models.EventAllocated, Size: 2, Granted: 2},
+		{Tick: 1, RequestID: 1, ClientID: 1, Action: models.EventCompleted},
+		{Tick: 1, RequestID: 2, ClientID: 2, Action: models.EventAllocated, Size: 1, Granted: 1},
+		{Tick: 2, RequestID: 4, ClientID: 2, Action: models.EventRejected, Size: 1},
+		{Tick: 3, RequestID: 2, ClientID: 2, Action: models.EventCompleted},
+		{Tick: 3, RequestID: 5, ClientID: 2, Action: models.EventShed, Size: 1},
+		{Tick: 4, RequestID: 3, ClientID: 2, Action: models.EventReneged, Size: 1},
+	}
+	settings := runSettings{
+		classes:  []models.ClientClass{{Name: "vip"}, {Name: "free"}},
+		vouchers: 4,
+	}
+
+	m := computeMetrics(settings, runWorkload{clients: clients, requests: requests, retries: retries}, events)
+
+	vip, free := m.Classes["vip"], m.Classes["free"]
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"vip requests", float64(vip.Requests), 1},
+		{"vip allocated", float64(vip.Allocated), 1},
+		{"vip units", float64(vip.Units), 2},
+		{"vip capacity share", vip.CapacityShare, 0.5},
+		{"vip mean response", vip.MeanResponse, 1},
+		{"free requests", float64(free.Requests), 3},
+		{"free retries", float64(free.Retries), 1},
+		{"free allocated", float64(free.Allocated), 1},
+		{"free rejected", float64(free.Rejected), 1},
+		{"free shed", float64(free.Shed), 1},
+		{"free reneged", float64(free.Reneged), 1},
+		{"free wait p50", free.WaitP50, 1},
+		{"free mean response", free.MeanResponse, 3},
+		{"free mean slowdown", free.MeanSlowdown, 1.5},
+		{"attempts", float64(m.Attempts), 5},
+		{"retries", float64(m.Retries), 1},
+		{"load amplification", m.LoadAmplification, 1.25},
+		{"max wait", float64(m.MaxWait), 3},
+		// vip 2/2 = 1, free 1/1 = 1
+		{"fairness index", m.FairnessIndex, 1},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{50, 5},
+		{95, 10},
+		{99, 10},
+		{100, 10},
+	}
+	for _, tt := range tests {
+		if got := percentile(values, tt.p); got != tt.want {
+			t.Errorf("p%v = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("empty p50 = %v, want 0", got)
+	}
+}
+
+func TestJainIndex(t *testing.T) {
+	clients := []models.Client{{ID: 1, Weight: 1}, {ID: 2, Weight: 1}, {ID: 3, Weight: 2}}
+	tests := []struct {
+		name      string
+		allocated map[int]float64
+		want      float64
+	}{
+		{"proportional to weight", map[int]float64{1: 1, 2: 1, 3: 2}, 1},
+		{"one client takes all", map[int]float64{1: 3}, 1.0 / 3},
+		// x = 2, 0, 1: 9 / (3*5)
+		{"uneven", map[int]float64{1: 2, 3: 2}, 0.6},
+	}
+	for _, tt := range tests {
+		if got := jainIndex(clients, tt.allocated); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: jain = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunSimulationMetrics(t *testing.T) {
+	s, _ := newTestService()
+	resp, err := s.RunSimulation(models.SimulationRequest{
+		SimulationWorkload: models.SimulationWorkload{TotalClients: 30, TotalVouchers: 10, Seed: 3},
+		Policy:             "hybrid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// metrics phải khớp với event stream trả về cùng response
+	allocated, units := map[int]bool{}, 0
+	for _, e := range resp.Events {
+		if e.Action == models.EventAllocated {
+			allocated[e.RequestID] = true
+			units += e.Granted
+		}
+	}
+	var requests, allocs, gotUnits int
+	for _, c := range resp.Metrics.Classes {
+		requests += c.Requests
+		allocs += c.Allocated
+		gotUnits += c.Units
+	}
+
+	if requests != resp.Simulation.TotalRequests {
+		t.Errorf("class requests = %d, want %d", requests, resp.Simulation.TotalRequests)
+	}
+	if allocs != len(allocated) || gotUnits != units {
+		t.Errorf("allocated = %d (%d units), events have %d (%d units)", allocs, gotUnits, len(allocated), units)
+	}
+	if units != 10 {
+		t.Errorf("units = %d, want all 10 vouchers handed out", units)
+	}
+	if f := resp.Metrics.FairnessIndex; f <= 0 || f > 1 {
+		t.Errorf("fairness index = %v, want within (0, 1]", f)
+	}
+	if resp.Metrics.Attempts != resp.Simulation.TotalRequests || resp.Metrics.LoadAmplification != 1 {
+		t.Errorf("attempts = %d, amplification = %v without retries", resp.Metrics.Attempts, resp.Metrics.LoadAmplification)
+	}
+}
@@ -2,45 +2,278 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"time"
 
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/config"
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/scheduler"
 	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/storage"
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/utils"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// slotStore is the part of storage.SlotStore a simulation allocates from
+type slotStore interface {
+	InitPool(ctx context.Context, simulationID, pool string, slots int) error
+	InitResource(ctx context.Context, simulationID, resource string, capacity int) error
+	TryAcquirePool(ctx context.Context, simulationID, pool string, n int) (bool, error)
+	AcquireUpToPool(ctx context.Context, simulationID, pool string, n int) (int, error)
+	TryAcquireResources(ctx context.Context, simulationID string, demand map[string]int) (bool, error)
+	ClearPools(ctx context.Context, simulationID string, pools []string) error
+	ClearResources(ctx context.Context, simulationID string, resources []string) error
+}
+
 type SimulateService struct {
 	logger    *logrus.Logger
-	slotStore *storage.SlotStore
+	slotStore slotStore
+	hybrid    scheduler.HybridConfig // default weights from config.yaml
+	classes   []models.ClientClass   // default class catalog from config.yaml
+	expr      string                 // default formula of policy=expr
+	tieBreak  scheduler.TieBreak     // default tie break of policy=priority
+	// sweepWorkers bounds the simulations a sweep runs at once
+	sweepWorkers int
 }
 
-func NewSimulateService(logger *logrus.Logger, store *storage.SlotStore) *SimulateService {
+func NewSimulateService(cfg *config.Config, logger *logrus.Logger, store *storage.SlotStore) *SimulateService {
 	return &SimulateService{
 		logger:    logger,
 		slotStore: store,
+		hybrid: scheduler.HybridConfig{
+			Alpha:        cfg.Simulation.Hybrid.Alpha,
+			Beta:         cfg.Simulation.Hybrid.Beta,
+			Gamma:        cfg.Simulation.Hybrid.Gamma,
+			WeightedDebt: cfg.Simulation.Hybrid.WeightedDebt,
+			DebtDecay:    scheduler.DebtDecay(cfg.Simulation.Hybrid.DebtDecay),
+			DebtHalfLife: cfg.Simulation.Hybrid.DebtHalfLife,
+			DebtWindow:   cfg.Simulation.Hybrid.DebtWindow,
+		},
+		classes:      cfg.Simulation.ClassCatalog(),
+		expr:         cfg.Simulation.Expr,
+		tieBreak:     scheduler.TieBreak(cfg.Simulation.TieBreak),
+		sweepWorkers: cfg.Simulation.SweepWorkers,
 	}
 }
+
+// runSettings are the validated knobs shared by generated and replayed runs
+type runSettings struct {
+	policy              string
+	vouchers            int            // total over all pools
+	pools               []models.Pool  // in declaration order
+	resources           map[string]int // capacity vector, replaces pools when set
+	seed                int64
+	service             scheduler.ServiceConfig
+	hybrid              scheduler.HybridConfig
+	expr                *scheduler.Expr // policy=expr only
+	tieBreak            scheduler.TieBreak
+	classes             []models.ClientClass
+	waitSnapshotEvery   int
+	fill                scheduler.FillMode
+	starvationThreshold int // wait reported as starvation, 0 = default
+	admission           scheduler.AdmissionConfig
+}
+
+// runWorkload is what gets scheduled: generated or read from a trace
+type runWorkload struct {
+	clients      []models.Client
+	requests     []models.Request
+	arrivalOrder []models.ClientArrival
+	retries      []models.Request // retry attempts the scheduler added, filled by execute
+}
+
 func (s *SimulateService) RunSimulation(
 	input models.SimulationRequest,
 ) (*models.SimulateResponse, error) {
 
+	settings, err := s.runSettings(input.SimulationWorkload, input.Policy, input.Hybrid, input.Expr)
+	if err != nil {
+		return nil, err
+	}
+	if input.TieBreak != "" {
+		settings.tieBreak = scheduler.TieBreak(input.TieBreak)
+	}
+	run, err := generateWorkload(input.SimulationWorkload, settings.classes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.execute(settings, run)
+}
+
+// generateWorkload draws the clients and requests of a generated run
+func generateWorkload(input models.SimulationWorkload, classes []models.ClientClass) (runWorkload, error) {
+	arrival, err := scheduler.NewArrivalModel(input.Arrival)
+	if err != nil {
+		return runWorkload{}, fmt.Errorf("%w: arrival: %v", utils.ErrInvalidRequest, err)
+	}
+
+	clients := scheduler.GenerateClients(input.Seed, input.TotalClients, classes)
+	requests, arrivalOrder := scheduler.GenerateRequests(clients, input.Seed, classes, arrival)
+
+	return runWorkload{
+		clients:      clients,
+		requests:     requests,
+		arrivalOrder: arrivalOrder,
+	}, nil
+}
+
+// RunReplay schedules a recorded trace instead of a generated workload
+func (s *SimulateService) RunReplay(
+	input models.ReplayRequest,
+	trace io.Reader,
+) (*models.SimulateResponse, error) {
+
+	settings, err := s.runSettings(models.SimulationWorkload{
+		TotalVouchers:       input.TotalVouchers,
+		Seed:                input.Seed,
+		WaitSnapshotEvery:   input.WaitSnapshotEvery,
+		Fill:                input.Fill,
+		StarvationThreshold: input.StarvationThreshold,
+		Admission:           replayAdmission(input),
+	}, input.Policy, nil, input.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := scheduler.ParseTrace(trace, scheduler.TraceFormat(input.Format))
+	if err != nil {
+		return nil, fmt.Errorf("%w: trace: %v", utils.ErrInvalidRequest, err)
+	}
+
+	tick := time.Duration(input.TickMillis) * time.Millisecond
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+	clients, requests, arrivalOrder, err := scheduler.TraceWorkload(records, settings.classes, tick)
+	if err != nil {
+		return nil, fmt.Errorf("%w: trace: %v", utils.ErrInvalidRequest, err)
+	}
+
+	return s.execute(settings, runWorkload{
+		clients:      clients,
+		requests:     requests,
+		arrivalOrder: arrivalOrder,
+	})
+}
+
+// runSettings validates the parts of the input that do not depend on the workload
+func (s *SimulateService) runSettings(
+	input models.SimulationWorkload,
+	policy string,
+	hybridParams *models.HybridParams,
+	exprSource string,
+) (runSettings, error) {
+
+	serviceConfig, err := newServiceConfig(input.Service)
+	if err != nil {
+		return runSettings{}, err
+	}
+	hybrid := s.hybridConfig(hybridParams)
+	if err := hybrid.Validate(); err != nil {
+		return runSettings{}, fmt.Errorf("%w: hybrid: %v", utils.ErrInvalidRequest, err)
+	}
+	var expr *scheduler.Expr
+	if policy == "expr" {
+		if exprSource == "" {
+			exprSource = s.expr
+		}
+		if expr, err = scheduler.CompileExpr(exprSource); err != nil {
+			return runSettings{}, fmt.Errorf("%w: expr: %v", utils.ErrInvalidRequest, err)
+		}
+	}
+	classes, err := s.classCatalog(input.Classes, input.TTL)
+	if err != nil {
+		return runSettings{}, err
+	}
+	var pools []models.Pool
+	if len(input.Resources) > 0 {
+		if err := checkDemands(classes, input.Resources); err != nil {
+			return runSettings{}, err
+		}
+	} else {
+		if pools, err = poolCatalog(input.Pools, input.TotalVouchers); err != nil {
+			return runSettings{}, err
+		}
+		if err := assignPools(classes, pools); err != nil {
+			return runSettings{}, err
+		}
+	}
+	vouchers := 0
+	for _, p := range pools {
+		vouchers += p.Slots
+	}
+
+	fill := scheduler.FillMode(input.Fill)
+	if fill == "" {
+		fill = scheduler.FillAllOrNothing
+	}
+	admission, err := newAdmissionConfig(input.Admission, classes)
+	if err != nil {
+		return runSettings{}, err
+	}
+
+	return runSettings{
+		policy:              policy,
+		vouchers:            vouchers,
+		pools:               pools,
+		resources:           input.Resources,
+		seed:                input.Seed,
+		service:             serviceConfig,
+		hybrid:              hybrid,
+		expr:                expr,
+		tieBreak:            s.tieBreak,
+		classes:             classes,
+		waitSnapshotEvery:   input.WaitSnapshotEvery,
+		fill:                fill,
+		starvationThreshold: input.StarvationThreshold,
+		admission:           admission,
+	}, nil
+}
+
+// execute schedules the workload and allocates slots in decision order
+func (s *SimulateService) execute(
+	settings runSettings,
+	run runWorkload,
+) (*models.SimulateResponse, error) {
+
 	ctx := context.Background()
 	simID := uuid.New().String()
+	hybrid := settings.hybrid
 
-	// 1. Init voucher
-	if err := s.slotStore.InitSlot(ctx, simID, input.TotalVouchers); err != nil {
-		return nil, err
+	// 1. Init voucher pools
+	poolNames := make([]string, 0, len(settings.pools))
+	poolSlots := make(map[string]int, len(settings.pools))
+	for _, p := range settings.pools {
+		poolNames = append(poolNames, p.Name)
+		poolSlots[p.Name] = p.Slots
+	}
+	defer s.slotStore.ClearPools(ctx, simID, poolNames)
+	for _, p := range settings.pools {
+		if err := s.slotStore.InitPool(ctx, simID, p.Name, p.Slots); err != nil {
+			return nil, err
+		}
 	}
 
-	// 2. Generate workload
-	clients := scheduler.GenerateClients(input.Seed, input.TotalClients)
-	requests, arrivalOrder := scheduler.GenerateRequests(clients, input.Seed)
+	resourceNames := make([]string, 0, len(settings.resources))
+	for r := range settings.resources {
+		resourceNames = append(resourceNames, r)
+	}
+	defer s.slotStore.ClearResources(ctx, simID, resourceNames)
+	for r, capacity := range settings.resources {
+		if err := s.slotStore.InitResource(ctx, simID, r, capacity); err != nil {
+			return nil, err
+		}
+	}
 
-	// 3. Scheduler selects strategy
-	strategyFactory := scheduler.NewStrategyFactory()
-	strategy := strategyFactory.Get(input.Policy)
+	// 2. Scheduler selects strategy
+	strategyFactory := scheduler.NewStrategyFactory(scheduler.StrategyConfig{
+		Hybrid:   hybrid,
+		Expr:     settings.expr,
+		TieBreak: settings.tieBreak,
+	})
+	strategy := strategyFactory.Get(settings.policy)
 	if strategy == nil {
 		// Fallback or error. For now, defaulting to hybrid if not found, or maybe just error?
 		// Given validation in DTO, input.Policy should be valid.
@@ -48,47 +281,431 @@ func (s *SimulateService) RunSimulation(
 		strategy = strategyFactory.Get("hybrid")
 	}
 
-	decisions := strategy.Schedule(requests)
+	workload := scheduler.NewWorkload(run.clients, run.requests, settings.seed)
+	workload.Service = settings.service
+	workload.WaitSnapshotEvery = settings.waitSnapshotEvery
+	if len(settings.pools) > 0 {
+		workload.Pools = poolSlots
+	}
+	workload.Resources = settings.resources
+	workload.Fill = settings.fill
+	workload.Admission = settings.admission
+	workload.Retry = scheduler.RetryPolicies(settings.classes)
+	decisions := strategy.Schedule(workload)
 
-	// 4. Execute decisions
+	// 3. Execute decisions
 	var events []models.Event
+	seenRetry := map[int]bool{}
 
 	for _, d := range decisions {
-		action := "rejected"
-
-		ok, err := s.slotStore.TryAcquire(ctx, simID, 1)
-		if err != nil {
-			return nil, err
-		}
-
-		if ok {
-			action = "allocated"
+		if d.Request.RetryOf != 0 && !seenRetry[d.Request.ID] {
+			seenRetry[d.Request.ID] = true
+			run.retries = append(run.retries, d.Request)
 		}
 
-		events = append(events, models.Event{
+		event := models.Event{
 			Tick:      d.Tick,
 			RequestID: d.Request.ID,
 			ClientID:  d.Request.ClientID,
 			Priority:  d.Request.Priority,
 			Score:     d.Score,
-			Action:    action,
-		})
-	}
+			Server:    d.Server,
+			Size:      d.Request.Size,
+			RetryOf:   d.Request.RetryOf,
+		}
+		if d.Request.RetryOf != 0 {
+			event.Attempt = d.Request.Attempt
+		}
+
+		switch d.Action {
+		case scheduler.ActionEnqueue:
+			event.Action = models.EventEnqueue
+		case scheduler.ActionWait:
+			event.Action = models.EventWait
+		case scheduler.ActionDrop:
+			event.Action = models.EventDrop
+		case scheduler.ActionPreempt:
+			event.Action = models.EventPreempted
+		case scheduler.ActionResume:
+			event.Action = models.EventResumed
+		case scheduler.ActionComplete:
+			event.Action = models.EventCompleted
+		case scheduler.ActionReject:
+			event.Action = models.EventRejected
+		case scheduler.ActionShed:
+			event.Action = models.EventShed
+		case scheduler.ActionRenege:
+			event.Action = models.EventReneged
+		case scheduler.ActionSelect:
+			event.Action = models.EventSelected
+			events = append(events, event)
 
-	s.slotStore.Clear(ctx, simID)
+			pool, granted, err := s.acquire(ctx, simID, d, settings.fill)
+			if err != nil {
+				return nil, err
+			}
 
-	// 5. BUILD RESPONSE
+			event.Action = models.EventRejected
+			if granted > 0 {
+				event.Action = models.EventAllocated
+				event.Granted = granted
+				event.Pool = pool
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	// 4. BUILD RESPONSE
+	attempts := append(run.requests[:len(run.requests):len(run.requests)], run.retries...)
 	resp := &models.SimulateResponse{
 		Simulation: models.Simulation{
 			ID:            simID,
-			Slots:         input.TotalVouchers,
-			TotalRequests: len(requests),
-			Policy:        "hybrid",
+			Slots:         settings.vouchers,
+			TotalRequests: len(run.requests),
+			Policy:        strategy.Name(),
 			CreatedAt:     time.Now(),
+			Classes:       settings.classes,
+			Resources:     settings.resources,
 		},
-		ArrivalOrder: arrivalOrder,
+		ArrivalOrder: run.arrivalOrder,
 		Events:       events,
+		DropRates:    classRates(workload.Clients, attempts, events, models.EventDrop),
+		ShedRates:    classRates(workload.Clients, attempts, events, models.EventShed),
+		Pools:        poolStats(settings.pools, attempts, events),
+	}
+	resp.Metrics = computeMetrics(settings, run, events)
+	if len(settings.resources) > 0 {
+		resp.DominantShares = dominantShares(attempts, settings.resources, events)
+	}
+	if strategy.Name() == "expr" {
+		resp.Simulation.Expr = settings.expr.String()
+	}
+	if strategy.Name() == "hybrid" {
+		resp.Simulation.Hybrid = &models.HybridWeights{
+			Alpha:        hybrid.Alpha,
+			Beta:         hybrid.Beta,
+			Gamma:        hybrid.Gamma,
+			WeightedDebt: hybrid.WeightedDebt,
+			DebtDecay:    string(hybrid.DebtDecay),
+			DebtHalfLife: hybrid.DebtHalfLife,
+			DebtWindow:   hybrid.DebtWindow,
+		}
 	}
 
 	return resp, nil
 }
+
+// acquire takes the units of a selected request from the first of its pools
+// that has all of them or, with FillPartial, whatever is left in the first
+// pool that is not empty
+func (s *SimulateService) acquire(ctx context.Context, simID string, d scheduler.Decision, fill scheduler.FillMode) (string, int, error) {
+	if len(d.Request.Resources) > 0 {
+		ok, err := s.slotStore.TryAcquireResources(ctx, simID, d.Request.Resources)
+		if err != nil || !ok {
+			return "", 0, err
+		}
+		return "", d.Request.Size, nil
+	}
+
+	for _, pool := range d.Request.Pools {
+		ok, err := s.slotStore.TryAcquirePool(ctx, simID, pool, d.Request.Size)
+		if err != nil {
+			return "", 0, err
+		}
+		if ok {
+			return pool, d.Request.Size, nil
+		}
+	}
+	if fill != scheduler.FillPartial {
+		return "", 0, nil
+	}
+
+	for _, pool := range d.Request.Pools {
+		granted, err := s.slotStore.AcquireUpToPool(ctx, simID, pool, d.Request.Size)
+		if err != nil {
+			return "", 0, err
+		}
+		if granted > 0 {
+			return pool, granted, nil
+		}
+	}
+	return "", 0, nil
+}
+
+// hybridConfig applies the per-request override on top of the configured weights
+func (s *SimulateService) hybridConfig(params *models.HybridParams) scheduler.HybridConfig {
+	cfg := s.hybrid
+	if params == nil {
+		return cfg
+	}
+
+	if params.Alpha != nil {
+		cfg.Alpha = *params.Alpha
+	}
+	if params.Beta != nil {
+		cfg.Beta = *params.Beta
+	}
+	if params.Gamma != nil {
+		cfg.Gamma = *params.Gamma
+	}
+	if params.WeightedDebt != nil {
+		cfg.WeightedDebt = *params.WeightedDebt
+	}
+	if params.DebtDecay != nil {
+		cfg.DebtDecay = scheduler.DebtDecay(*params.DebtDecay)
+	}
+	if params.DebtHalfLife != nil {
+		cfg.DebtHalfLife = *params.DebtHalfLife
+	}
+	if params.DebtWindow != nil {
+		cfg.DebtWindow = *params.DebtWindow
+	}
+
+	return cfg
+}
+
+// classCatalog picks the request catalog over the configured one and applies
+// the per-class TTL overrides
+func (s *SimulateService) classCatalog(
+	override []models.ClientClass,
+	ttl map[string]int,
+) ([]models.ClientClass, error) {
+
+	source := s.classes
+	if len(override) > 0 {
+		source = override
+	}
+	classes := make([]models.ClientClass, len(source))
+	copy(classes, source)
+
+	for name, t := range ttl {
+		found := false
+		for i := range classes {
+			if classes[i].Name == name {
+				classes[i].TTL = t
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: ttl for unknown class %q", utils.ErrInvalidRequest, name)
+		}
+	}
+
+	if err := scheduler.ValidateClasses(classes); err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrInvalidRequest, err)
+	}
+
+	return classes, nil
+}
+
+// poolCatalog returns the requested pools, or a single default pool
+// holding all the vouchers
+func poolCatalog(pools []models.Pool, vouchers int) ([]models.Pool, error) {
+	if len(pools) == 0 {
+		if vouchers <= 0 {
+			return nil, fmt.Errorf("%w: total_vouchers must be > 0", utils.ErrInvalidRequest)
+		}
+		return []models.Pool{{Name: scheduler.DefaultPool, Slots: vouchers}}, nil
+	}
+
+	seen := make(map[string]bool, len(pools))
+	for _, p := range pools {
+		if seen[p.Name] {
+			return nil, fmt.Errorf("%w: duplicate pool %q", utils.ErrInvalidRequest, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return pools, nil
+}
+
+// assignPools checks the pool preferences of every class and points
+// classes without one at the first pool
+func assignPools(classes []models.ClientClass, pools []models.Pool) error {
+	known := make(map[string]bool, len(pools))
+	for _, p := range pools {
+		known[p.Name] = true
+	}
+
+	for i := range classes {
+		if len(classes[i].Pools) == 0 {
+			classes[i].Pools = []string{pools[0].Name}
+			continue
+		}
+		for _, name := range classes[i].Pools {
+			if !known[name] {
+				return fmt.Errorf("%w: class %q: unknown pool %q", utils.ErrInvalidRequest, classes[i].Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkDemands makes sure every class asks for something, and only for
+// resources the simulation has
+func checkDemands(classes []models.ClientClass, resources map[string]int) error {
+	for _, c := range classes {
+		total := 0
+		for r, d := range c.Demand {
+			if _, ok := resources[r]; !ok {
+				return fmt.Errorf("%w: class %q: unknown resource %q", utils.ErrInvalidRequest, c.Name, r)
+			}
+			total += d
+		}
+		if total == 0 {
+			return fmt.Errorf("%w: class %q: needs a demand vector with resources", utils.ErrInvalidRequest, c.Name)
+		}
+	}
+	return nil
+}
+
+// newServiceConfig converts the request capacity block into a scheduler.ServiceConfig
+func newServiceConfig(params *models.ServiceParams) (scheduler.ServiceConfig, error) {
+	cfg := scheduler.DefaultServiceConfig()
+	if params == nil {
+		return cfg, nil
+	}
+
+	if params.Rate > 0 {
+		cfg.Rate = params.Rate
+	}
+	if params.Servers > 0 {
+		cfg.Servers = params.Servers
+	}
+	for _, w := range params.Schedule {
+		cfg.Schedule = append(cfg.Schedule, scheduler.CapacityWindow{
+			FromTick: w.FromTick,
+			Rate:     w.Rate,
+		})
+	}
+
+	cfg, err := cfg.Normalize()
+	if err != nil {
+		return cfg, fmt.Errorf("%w: %v", utils.ErrInvalidRequest, err)
+	}
+
+	return cfg, nil
+}
+
+// newAdmissionConfig converts the request admission block into a scheduler.AdmissionConfig
+func newAdmissionConfig(params *models.AdmissionParams, classes []models.ClientClass) (scheduler.AdmissionConfig, error) {
+	if params == nil {
+		return scheduler.AdmissionConfig{}, nil
+	}
+
+	known := make(map[string]bool, len(classes))
+	for _, c := range classes {
+		known[c.Name] = true
+	}
+	for name := range params.ClassWeights {
+		if !known[name] {
+			return scheduler.AdmissionConfig{}, fmt.Errorf("%w: admission: unknown class %q", utils.ErrInvalidRequest, name)
+		}
+	}
+
+	cfg, err := scheduler.AdmissionConfig{
+		MaxQueue:     params.MaxQueue,
+		Policy:       scheduler.AdmissionPolicy(params.Policy),
+		MinQueue:     params.MinQueue,
+		ClassWeights: params.ClassWeights,
+	}.Normalize()
+	if err != nil {
+		return cfg, fmt.Errorf("%w: admission: %v", utils.ErrInvalidRequest, err)
+	}
+	return cfg, nil
+}
+
+// replayAdmission is the admission block of the replay form fields
+func replayAdmission(input models.ReplayRequest) *models.AdmissionParams {
+	if input.MaxQueue == 0 {
+		return nil
+	}
+	return &models.AdmissionParams{MaxQueue: input.MaxQueue, Policy: input.Admission}
+}
+
+// classRates is the share of each class's requests (retry attempts
+// included) that ended with action
+func classRates(clients map[int]models.Client, requests []models.Request, events []models.Event, action string) map[string]float64 {
+	total := map[string]int{}
+	for _, r := range requests {
+		total[clients[r.ClientID].Class]++
+	}
+
+	matched := map[string]int{}
+	for _, e := range events {
+		if e.Action == action {
+			matched[clients[e.ClientID].Class]++
+		}
+	}
+
+	rates := make(map[string]float64, len(total))
+	for class, n := range total {
+		rates[class] = float64(matched[class]) / float64(n)
+	}
+
+	return rates
+}
+
+// poolStats sums the allocated events per pool, events without one of
+// pools (multi-resource runs) are left out
+func poolStats(pools []models.Pool, requests []models.Request, events []models.Event) []models.PoolStats {
+	preferred := make(map[int]string, len(requests))
+	for _, r := range requests {
+		if len(r.Pools) > 0 {
+			preferred[r.ID] = r.Pools[0]
+		}
+	}
+
+	index := make(map[string]int, len(pools))
+	stats := make([]models.PoolStats, len(pools))
+	for i, p := range pools {
+		index[p.Name] = i
+		stats[i] = models.PoolStats{Name: p.Name, Slots: p.Slots, Remaining: p.Slots}
+	}
+
+	for _, e := range events {
+		if e.Action != models.EventAllocated {
+			continue
+		}
+		i, ok := index[e.Pool]
+		if !ok {
+			continue // multi-resource run, nothing comes from a pool
+		}
+		st := &stats[i]
+		st.Allocated += e.Granted
+		st.Remaining -= e.Granted
+		st.Requests++
+		if preferred[e.RequestID] != e.Pool {
+			st.Substitutes++
+		}
+	}
+
+	return stats
+}
+
+// dominantShares is the dominant share each client ends the run with
+func dominantShares(requests []models.Request, capacity map[string]int, events []models.Event) map[int]float64 {
+	demand := make(map[int]map[string]int, len(requests))
+	for _, r := range requests {
+		demand[r.ID] = r.Resources
+	}
+
+	allocated := map[int]map[string]int{}
+	for _, r := range requests {
+		allocated[r.ClientID] = map[string]int{}
+	}
+	for _, e := range events {
+		if e.Action != models.EventAllocated {
+			continue
+		}
+		for r, d := range demand[e.RequestID] {
+			allocated[e.ClientID][r] += d
+		}
+	}
+
+	shares := make(map[int]float64, len(allocated))
+	for clientID, alloc := range allocated {
+		shares[clientID] = scheduler.DominantShare(alloc, capacity)
+	}
+	return shares
+}
@@ -0,0 +1,640 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/scheduler"
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPoolCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		pools    []models.Pool
+		vouchers int
+		want     []models.Pool
+		wantErr  string
+	}{
+		{
+			name:     "default pool from total_vouchers",
+			vouchers: 10,
+			want:     []models.Pool{{Name: scheduler.DefaultPool, Slots: 10}},
+		},
+		{
+			name:    "no pools and no vouchers",
+			wantErr: "total_vouchers must be > 0",
+		},
+		{
+			name:     "pools win over total_vouchers",
+			pools:    []models.Pool{{Name: "gpu", Slots: 2}, {Name: "cpu", Slots: 8}},
+			vouchers: 10,
+			want:     []models.Pool{{Name: "gpu", Slots: 2}, {Name: "cpu", Slots: 8}},
+		},
+		{
+			name:    "duplicate pool",
+			pools:   []models.Pool{{Name: "gpu", Slots: 2}, {Name: "gpu", Slots: 8}},
+			wantErr: `duplicate pool "gpu"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := poolCatalog(tt.pools, tt.vouchers)
+			if !checkErr(t, err, tt.wantErr) {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignPools(t *testing.T) {
+	pools := []models.Pool{{Name: "gpu", Slots: 2}, {Name: "cpu", Slots: 8}}
+
+	classes := []models.ClientClass{
+		{Name: "premium", Pools: []string{"cpu", "gpu"}},
+		{Name: "free"},
+	}
+	if err := assignPools(classes, pools); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(classes[0].Pools, []string{"cpu", "gpu"}) {
+		t.Errorf("premium pools = %v, want its own preference", classes[0].Pools)
+	}
+	if !reflect.DeepEqual(classes[1].Pools, []string{"gpu"}) {
+		t.Errorf("free pools = %v, want the first pool", classes[1].Pools)
+	}
+
+	err := assignPools([]models.ClientClass{{Name: "free", Pools: []string{"tpu"}}}, pools)
+	checkErr(t, err, `class "free": unknown pool "tpu"`)
+}
+
+// checkErr reports whether the caller should go on checking the result
+func checkErr(t *testing.T, err error, want string) bool {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return true
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("err = %v, want %q", err, want)
+	}
+	if !errors.Is(err, utils.ErrInvalidRequest) {
+		t.Errorf("err = %v, want it to wrap ErrInvalidRequest", err)
+	}
+	return false
+}
+
+func TestRunSettings(t *testing.T) {
+	s := &SimulateService{
+		hybrid:   scheduler.HybridConfig{Alpha: 1, Beta: 1, Gamma: 1},
+		classes:  scheduler.DefaultClasses(),
+		expr:     "priority",
+		tieBreak: scheduler.TieBreakArrival,
+	}
+	decay := "window"
+
+	tests := []struct {
+		name    string
+		input   models.SimulationWorkload
+		policy  string
+		hybrid  *models.HybridParams
+		expr    string
+		wantErr string
+		check   func(t *testing.T, st runSettings)
+	}{
+		{
+			name:   "defaults",
+			input:  models.SimulationWorkload{TotalVouchers: 10},
+			policy: "hybrid",
+			check: func(t *testing.T, st runSettings) {
+				if st.vouchers != 10 || len(st.pools) != 1 || st.pools[0].Name != scheduler.DefaultPool {
+					t.Errorf("pools = %v, vouchers = %d", st.pools, st.vouchers)
+				}
+				if st.fill != scheduler.FillAllOrNothing || st.tieBreak != scheduler.TieBreakArrival {
+					t.Errorf("fill = %q, tie break = %q", st.fill, st.tieBreak)
+				}
+				for _, c := range st.classes {
+					if !reflect.DeepEqual(c.Pools, []string{scheduler.DefaultPool}) {
+						t.Errorf("class %q pools = %v", c.Name, c.Pools)
+					}
+				}
+				if s.classes[0].Pools != nil {
+					t.Error("runSettings changed the configured catalog")
+				}
+			},
+		},
+		{
+			name:   "vouchers summed over pools",
+			input:  models.SimulationWorkload{TotalVouchers: 100, Pools: []models.Pool{{Name: "gpu", Slots: 2}, {Name: "cpu", Slots: 3}}},
+			policy: "fifo",
+			check: func(t *testing.T, st runSettings) {
+				if st.vouchers != 5 {
+					t.Errorf("vouchers = %d, want 5", st.vouchers)
+				}
+			},
+		},
+		{
+			name: "ttl override",
+			input: models.SimulationWorkload{
+				TotalVouchers: 10,
+				TTL:           map[string]int{"free": 7},
+			},
+			policy: "edf",
+			check: func(t *testing.T, st runSettings) {
+				for _, c := range st.classes {
+					if want := map[string]int{"free": 7}[c.Name]; c.TTL != want {
+						t.Errorf("class %q ttl = %d, want %d", c.Name, c.TTL, want)
+					}
+				}
+			},
+		},
+		{
+			name:    "ttl for unknown class",
+			input:   models.SimulationWorkload{TotalVouchers: 10, TTL: map[string]int{"gold": 7}},
+			policy:  "edf",
+			wantErr: `ttl for unknown class "gold"`,
+		},
+		{
+			name:   "expr falls back to the configured formula",
+			input:  models.SimulationWorkload{TotalVouchers: 10},
+			policy: "expr",
+			check: func(t *testing.T, st runSettings) {
+				if st.expr == nil {
+					t.Error("expr not compiled")
+				}
+			},
+		},
+		{
+			name:    "bad expr",
+			input:   models.SimulationWorkload{TotalVouchers: 10},
+			policy:  "expr",
+			expr:    "priority +",
+			wantErr: "expr:",
+		},
+		{
+			name:    "bad hybrid",
+			input:   models.SimulationWorkload{TotalVouchers: 10},
+			policy:  "hybrid",
+			hybrid:  &models.HybridParams{DebtDecay: &decay},
+			wantErr: "hybrid: debt_window must be > 0",
+		},
+		{
+			name:    "resources need demand vectors",
+			input:   models.SimulationWorkload{Resources: map[string]int{"cpu": 4}},
+			policy:  "drf",
+			wantErr: `class "vip": needs a demand vector`,
+		},
+		{
+			name: "resources replace pools",
+			input: models.SimulationWorkload{
+				Resources: map[string]int{"cpu": 4},
+				Classes:   []models.ClientClass{{Name: "batch", Share: 1, Weight: 1, Priority: 1, MinRequests: 1, MaxRequests: 1, Demand: map[string]int{"cpu": 2}}},
+			},
+			policy: "drf",
+			check: func(t *testing.T, st runSettings) {
+				if st.pools != nil || st.vouchers != 0 || st.resources["cpu"] != 4 {
+					t.Errorf("pools = %v, vouchers = %d, resources = %v", st.pools, st.vouchers, st.resources)
+				}
+			},
+		},
+		{
+			name: "admission for unknown class",
+			input: models.SimulationWorkload{
+				TotalVouchers: 10,
+				Admission:     &models.AdmissionParams{MaxQueue: 4, Policy: "early_drop", ClassWeights: map[string]float64{"gold": 1}},
+			},
+			policy:  "fifo",
+			wantErr: `admission: unknown class "gold"`,
+		},
+		{
+			name:    "bad service",
+			input:   models.SimulationWorkload{TotalVouchers: 10, Service: &models.ServiceParams{Schedule: []models.CapacityWindow{{FromTick: 5, Rate: 2}, {FromTick: 9, Rate: 0}}}},
+			policy:  "fifo",
+			wantErr: "last capacity window must have rate > 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := s.runSettings(tt.input, tt.policy, tt.hybrid, tt.expr)
+			if !checkErr(t, err, tt.wantErr) {
+				return
+			}
+			if st.policy != tt.policy {
+				t.Errorf("policy = %q, want %q", st.policy, tt.policy)
+			}
+			tt.check(t, st)
+		})
+	}
+}
+
+// memSlotStore is an in-memory slotStore with the semantics of the redis one
+type memSlotStore struct {
+	mu   sync.Mutex
+	left map[string]int // "simID/pool/name" or "simID/resource/name" -> units left
+}
+
+func newMemSlotStore() *memSlotStore {
+	return &memSlotStore{left: map[string]int{}}
+}
+
+func (m *memSlotStore) init(key string, n int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.left[key]; ok {
+		return fmt.Errorf("slot already initialized for simulation %s", key)
+	}
+	m.left[key] = n
+	return nil
+}
+
+func (m *memSlotStore) InitPool(_ context.Context, simID, pool string, slots int) error {
+	return m.init(simID+"/pool/"+pool, slots)
+}
+
+func (m *memSlotStore) InitResource(_ context.Context, simID, resource string, capacity int) error {
+	return m.init(simID+"/resource/"+resource, capacity)
+}
+
+func (m *memSlotStore) TryAcquirePool(_ context.Context, simID, pool string, n int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := simID + "/pool/" + pool
+	if m.left[key] < n {
+		return false, nil
+	}
+	m.left[key] -= n
+	return true, nil
+}
+
+func (m *memSlotStore) AcquireUpToPool(_ context.Context, simID, pool string, n int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := simID + "/pool/" + pool
+	granted := min(n, max(m.left[key], 0))
+	m.left[key] -= granted
+	return granted, nil
+}
+
+func (m *memSlotStore) TryAcquireResources(_ context.Context, simID string, demand map[string]int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for r, d := range demand {
+		if m.left[simID+"/resource/"+r] < d {
+			return false, nil
+		}
+	}
+	for r, d := range demand {
+		m.left[simID+"/resource/"+r] -= d
+	}
+	return true, nil
+}
+
+func (m *memSlotStore) ClearPools(_ context.Context, simID string, pools []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, pool := range pools {
+		delete(m.left, simID+"/pool/"+pool)
+	}
+	return nil
+}
+
+func (m *memSlotStore) ClearResources(_ context.Context, simID string, resources []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range resources {
+		delete(m.left, simID+"/resource/"+r)
+	}
+	return nil
+}
+
+// newTestService is a SimulateService on the default catalog and an in-memory store
+func newTestService() (*SimulateService, *memSlotStore) {
+	store := newMemSlotStore()
+	return &SimulateService{
+		logger:    logrus.New(),
+		slotStore: store,
+		hybrid:    scheduler.HybridConfig{Alpha: 1, Beta: 0.1, Gamma: 0.5},
+		classes:   scheduler.DefaultClasses(),
+		expr:      "priority",
+		tieBreak:  scheduler.TieBreakArrival,
+	}, store
+}
+
+func TestPoolStats(t *testing.T) {
+	pools := []models.Pool{{Name: "gpu", Slots: 4}, {Name: "cpu", Slots: 6}}
+	requests := []models.Request{
+		{ID: 1, Pools: []string{"gpu", "cpu"}},
+		{ID: 2, Pools: []string{"gpu", "cpu"}},
+		{ID: 3, Pools: []string{"cpu"}},
+		{ID: 4},
+	}
+	events := []models.Event{
+		{RequestID: 1, Action: models.EventAllocated, Pool: "gpu", Granted: 3},
+		{RequestID: 2, Action: models.EventAllocated, Pool: "cpu", Granted: 2}, // thay thế
+		{RequestID: 3, Action: models.EventSelected, Pool: "cpu", Granted: 5},
+		{RequestID: 3, Action: models.EventAllocated, Pool: "cpu", Granted: 1},
+		{RequestID: 4, Action: models.EventAllocated, Granted: 1}, // không từ pool nào
+	}
+
+	got := poolStats(pools, requests, events)
+	want := []models.PoolStats{
+		{Name: "gpu", Slots: 4, Allocated: 3, Remaining: 1, Requests: 1},
+		{Name: "cpu", Slots: 6, Allocated: 3, Remaining: 3, Requests: 2, Substitutes: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	if got := poolStats(nil, requests, events); len(got) != 0 {
+		t.Errorf("no pools: got %+v", got)
+	}
+}
+
+func TestRunSimulationResources(t *testing.T) {
+	s, store := newTestService()
+	capacity := map[string]int{"cpu": 8, "gpu": 2}
+	input := models.SimulationRequest{
+		SimulationWorkload: models.SimulationWorkload{
+			TotalClients: 20,
+			Seed:         5,
+			Resources:    capacity,
+			Classes: []models.ClientClass{
+				{Name: "train", Share: 1, Weight: 1, Priority: 1, MinRequests: 1, MaxRequests: 2, Demand: map[string]int{"cpu": 1, "gpu": 1}},
+				{Name: "batch", Share: 2, Weight: 1, Priority: 2, MinRequests: 1, MaxRequests: 2, Demand: map[string]int{"cpu": 2}},
+			},
+		},
+		Policy: "drf",
+	}
+
+	resp, err := s.RunSimulation(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demand := map[string]map[string]int{"train": {"cpu": 1, "gpu": 1}, "batch": {"cpu": 2}}
+	classOf := map[int]string{}
+	for _, a := range resp.ArrivalOrder {
+		classOf[a.ClientID] = a.Class
+	}
+	used := map[string]int{}
+	allocated := 0
+	for _, e := range resp.Events {
+		if e.Action != models.EventAllocated {
+			continue
+		}
+		allocated++
+		if e.Pool != "" {
+			t.Errorf("request %d: pool = %q in a multi-resource run", e.RequestID, e.Pool)
+		}
+		for r, d := range demand[classOf[e.ClientID]] {
+			used[r] += d
+		}
+	}
+	if allocated == 0 {
+		t.Fatal("nothing allocated")
+	}
+	for r, u := range used {
+		if u > capacity[r] {
+			t.Errorf("%s: %d allocated over a capacity of %d", r, u, capacity[r])
+		}
+	}
+	if len(resp.Pools) != 0 {
+		t.Errorf("pools = %+v, want none", resp.Pools)
+	}
+	if len(resp.DominantShares) == 0 {
+		t.Error("dominant_shares missing")
+	}
+	for id, share := range resp.DominantShares {
+		if share < 0 || share > 1 {
+			t.Errorf("client %d: dominant share %v", id, share)
+		}
+	}
+	if len(store.left) != 0 {
+		t.Errorf("resource counters left behind: %v", store.left)
+	}
+}
+
+func TestHybridConfig(t *testing.T) {
+	s, _ := newTestService()
+	zero, two, weighted := 0.0, 2.0, true
+
+	// field nil giữ giá trị config, field 0 vẫn là override
+	got := s.hybridConfig(&models.HybridParams{Alpha: &two, Gamma: &zero, WeightedDebt: &weighted})
+	want := scheduler.HybridConfig{Alpha: 2, Beta: 0.1, Gamma: 0, WeightedDebt: true}
+	if got != want {
+		t.Errorf("override = %+v, want %+v", got, want)
+	}
+	if got := s.hybridConfig(nil); got != s.hybrid {
+		t.Errorf("no override = %+v, want %+v", got, s.hybrid)
+	}
+}
+
+func TestRunSimulationHybridWeights(t *testing.T) {
+	s, _ := newTestService()
+	beta := 3.0
+	workload := models.SimulationWorkload{TotalClients: 10, TotalVouchers: 5, Seed: 1}
+
+	resp, err := s.RunSimulation(models.SimulationRequest{
+		SimulationWorkload: workload,
+		Policy:             "hybrid",
+		Hybrid:             &models.HybridParams{Beta: &beta},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.HybridWeights{Alpha: 1, Beta: 3, Gamma: 0.5}
+	if resp.Simulation.Hybrid == nil || *resp.Simulation.Hybrid != want {
+		t.Errorf("hybrid = %+v, want %+v", resp.Simulation.Hybrid, want)
+	}
+
+	// policy khác bỏ qua override và không echo weights
+	resp, err = s.RunSimulation(models.SimulationRequest{
+		SimulationWorkload: workload,
+		Policy:             "fifo",
+		Hybrid:             &models.HybridParams{Beta: &beta},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Simulation.Hybrid != nil {
+		t.Errorf("fifo hybrid = %+v, want nil", resp.Simulation.Hybrid)
+	}
+}
+
+func TestClassRates(t *testing.T) {
+	clients := map[int]models.Client{1: {ID: 1, Class: "vip"}, 2: {ID: 2, Class: "free"}}
+	requests := []models.Request{{ID: 1, ClientID: 1}, {ID: 2, ClientID: 2}, {ID: 3, ClientID: 2}, {ID: 4, ClientID: 2}}
+	events := []models.Event{
+		{RequestID: 1, ClientID: 1, Action: models.EventAllocated},
+		{RequestID: 2, ClientID: 2, Action: models.EventDrop},
+		{RequestID: 3, ClientID: 2, Action: models.EventShed},
+		{RequestID: 4, ClientID: 2, Action: models.EventDrop},
+	}
+
+	got := classRates(clients, requests, events, models.EventDrop)
+	want := map[string]float64{"vip": 0, "free": 2.0 / 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("drop rates = %v, want %v", got, want)
+	}
+}
+
+func TestRunSimulationDrops(t *testing.T) {
+	s, _ := newTestService()
+	resp, err := s.RunSimulation(models.SimulationRequest{
+		SimulationWorkload: models.SimulationWorkload{
+			TotalClients:  40,
+			TotalVouchers: 1000,
+			Seed:          2,
+			Classes: []models.ClientClass{
+				{Name: "paid", Share: 1, Weight: 1, Priority: 1, MinRequests: 1, MaxRequests: 2},
+				{Name: "free", Share: 1, Weight: 1, Priority: 2, MinRequests: 1, MaxRequests: 2, TTL: 2},
+			},
+		},
+		Policy: "fifo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	classOf := map[int]string{}
+	for _, a := range resp.ArrivalOrder {
+		classOf[a.ClientID] = a.Class
+	}
+	requests, dropped, allocated := map[string]int{}, map[string]int{}, map[int]bool{}
+	for _, e := range resp.Events {
+		switch e.Action {
+		case models.EventEnqueue:
+			requests[classOf[e.ClientID]]++
+		case models.EventDrop:
+			dropped[classOf[e.ClientID]]++
+			if allocated[e.RequestID] {
+				t.Errorf("request %d dropped after it was allocated", e.RequestID)
+			}
+		case models.EventAllocated:
+			allocated[e.RequestID] = true
+		}
+	}
+
+	// 1 request/tick cho cả burst: free hết hạn trong queue, paid không có TTL
+	if dropped["free"] == 0 || dropped["paid"] != 0 {
+		t.Fatalf("dropped = %v, want free only", dropped)
+	}
+	for class, n := range requests {
+		if want := float64(dropped[class]) / float64(n); resp.DropRates[class] != want {
+			t.Errorf("%s drop rate = %v, want %v", class, resp.DropRates[class], want)
+		}
+	}
+}
+
+func TestRunSimulationLifecycle(t *testing.T) {
+	s, _ := newTestService()
+	resp, err := s.RunSimulation(models.SimulationRequest{
+		SimulationWorkload: models.SimulationWorkload{
+			TotalClients:      30,
+			TotalVouchers:     25,
+			Seed:              4,
+			WaitSnapshotEvery: 3,
+			Classes: []models.ClientClass{
+				{Name: "paid", Share: 1, Weight: 2, Priority: 1, MinRequests: 1, MaxRequests: 2, MaxService: 3},
+				{Name: "free", Share: 2, Weight: 1, Priority: 2, MinRequests: 1, MaxRequests: 2, TTL: 3},
+			},
+		},
+		Policy: "hybrid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mỗi request: enqueue -> wait* -> selected -> allocated -> completed,
+	// hoặc kết thúc bằng rejected/drop; tới sau khi hết voucher thì bị
+	// rejected ngay, không enqueue; tick không giảm
+	next := map[string][]string{
+		"":                    {models.EventEnqueue, models.EventRejected},
+		models.EventEnqueue:   {models.EventWait, models.EventSelected, models.EventRejected, models.EventDrop},
+		models.EventWait:      {models.EventWait, models.EventSelected, models.EventRejected, models.EventDrop},
+		models.EventSelected:  {models.EventAllocated, models.EventRejected},
+		models.EventAllocated: {models.EventCompleted},
+	}
+	last, lastTick := map[int]string{}, map[int]int{}
+	waits := 0
+	for _, e := range resp.Events {
+		if e.Action == models.EventWait {
+			waits++
+		}
+		prev := last[e.RequestID]
+		if !slices.Contains(next[prev], e.Action) {
+			t.Fatalf("request %d: %s after %q", e.RequestID, e.Action, prev)
+		}
+		if prev != "" && e.Tick < lastTick[e.RequestID] {
+			t.Fatalf("request %d: %s at tick %d before tick %d", e.RequestID, e.Action, e.Tick, lastTick[e.RequestID])
+		}
+		last[e.RequestID], lastTick[e.RequestID] = e.Action, e.Tick
+	}
+
+	if len(last) != resp.Simulation.TotalRequests {
+		t.Errorf("%d requests have events, want %d", len(last), resp.Simulation.TotalRequests)
+	}
+	ended := map[string]int{}
+	for id, action := range last {
+		switch action {
+		case models.EventCompleted, models.EventRejected, models.EventDrop:
+			ended[action]++
+		default:
+			t.Errorf("request %d ends with %s", id, action)
+		}
+	}
+	if ended[models.EventCompleted] == 0 || ended[models.EventRejected] == 0 || ended[models.EventDrop] == 0 || waits == 0 {
+		t.Errorf("outcomes = %v with %d wait events, want completed, rejected and dropped requests", ended, waits)
+	}
+}
+
+func TestRunSimulationFill(t *testing.T) {
+	for _, fill := range []string{"all_or_nothing", "partial"} {
+		t.Run(fill, func(t *testing.T) {
+			s, _ := newTestService()
+			resp, err := s.RunSimulation(models.SimulationRequest{
+				SimulationWorkload: models.SimulationWorkload{
+					TotalClients:  5,
+					TotalVouchers: 5,
+					Seed:          1,
+					Fill:          fill,
+					Classes:       []models.ClientClass{{Name: "bulk", Share: 1, Weight: 1, Priority: 1, MinRequests: 1, MaxRequests: 1, MinSize: 3, MaxSize: 3}},
+				},
+				Policy: "fifo",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 3 + 2: request thứ hai chỉ nhận phần còn lại khi fill=partial
+			var granted []int
+			for _, e := range resp.Events {
+				if e.Action == models.EventAllocated {
+					granted = append(granted, e.Granted)
+				}
+			}
+			want := []int{3}
+			if fill == "partial" {
+				want = []int{3, 2}
+			}
+			if !reflect.DeepEqual(granted, want) {
+				t.Errorf("granted = %v, want %v", granted, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/utils"
+)
+
+// MaxSweepRuns bounds combinations x seeds of one sweep
+const MaxSweepRuns = 1000
+
+// maxRangeValues bounds how many values a from..to..step range expands to
+const maxRangeValues = 100
+
+// Sweep runs policy=hybrid for every parameter combination of the grid on
+// every seed, at most sweepWorkers simulations at a time, and marks the
+// combinations on the fairness / priority-satisfaction Pareto frontier.
+func (s *SimulateService) Sweep(
+	input models.SweepRequest,
+) (*models.SweepResponse, error) {
+
+	combos, err := sweepCombinations(input.Hybrid, input.Grid)
+	if err != nil {
+		return nil, err
+	}
+	runs := len(combos) * len(input.Seeds)
+	if runs > MaxSweepRuns {
+		return nil, fmt.Errorf("%w: sweep has %d runs (combinations x seeds), max is %d", utils.ErrInvalidRequest, runs, MaxSweepRuns)
+	}
+
+	// settings[c][i] là cấu hình của combination c trên seed i
+	settings := make([][]runSettings, len(combos))
+	for c, params := range combos {
+		for _, seed := range input.Seeds {
+			workload := input.SimulationWorkload
+			workload.Seed = seed
+			st, err := s.runSettings(workload, "hybrid", params, "")
+			if err != nil {
+				return nil, err
+			}
+			settings[c] = append(settings[c], st)
+		}
+	}
+
+	// mỗi seed sinh workload 1 lần, dùng chung cho mọi combination
+	workloads := make([]runWorkload, len(input.Seeds))
+	for i, seed := range input.Seeds {
+		workload := input.SimulationWorkload
+		workload.Seed = seed
+		if workloads[i], err = generateWorkload(workload, settings[0][i].classes); err != nil {
+			return nil, err
+		}
+	}
+
+	type sweepJob struct{ combo, seed int }
+	jobs := make(chan sweepJob)
+	metrics := make([][]sweepMetrics, len(combos))
+	for c := range metrics {
+		metrics[c] = make([]sweepMetrics, len(input.Seeds))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	workers := min(s.sweepWorkers, runs)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				st, run := settings[job.combo][job.seed], workloads[job.seed]
+				resp, err := s.execute(st, run)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					continue
+				}
+				metrics[job.combo][job.seed] = sweepMetrics{
+					fairness:     resp.Metrics.FairnessIndex,
+					satisfaction: prioritySatisfaction(run, resp.Events),
+					starved:      float64(resp.Metrics.Starved),
+				}
+			}
+		}()
+	}
+	for c := range combos {
+		for i := range input.Seeds {
+			jobs <- sweepJob{combo: c, seed: i}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	resp := &models.SweepResponse{
+		Runs:    runs,
+		Seeds:   input.Seeds,
+		Results: make([]models.SweepResult, len(combos)),
+	}
+	for c := range combos {
+		var mean sweepMetrics
+		for _, m := range metrics[c] {
+			mean.fairness += m.fairness / float64(len(input.Seeds))
+			mean.satisfaction += m.satisfaction / float64(len(input.Seeds))
+			mean.starved += m.starved / float64(len(input.Seeds))
+		}
+
+		hybrid := settings[c][0].hybrid
+		resp.Results[c] = models.SweepResult{
+			Hybrid: models.HybridWeights{
+				Alpha:        hybrid.Alpha,
+				Beta:         hybrid.Beta,
+				Gamma:        hybrid.Gamma,
+				WeightedDebt: hybrid.WeightedDebt,
+				DebtDecay:    string(hybrid.DebtDecay),
+				DebtHalfLife: hybrid.DebtHalfLife,
+				DebtWindow:   hybrid.DebtWindow,
+			},
+			FairnessIndex:        mean.fairness,
+			PrioritySatisfaction: mean.satisfaction,
+			Starved:              mean.starved,
+		}
+	}
+	markPareto(resp.Results)
+
+	return resp, nil
+}
+
+type sweepMetrics struct {
+	fairness     float64
+	satisfaction float64
+	starved      float64
+}
+
+// sweepCombinations expands the grid into the cartesian product of its
+// values on top of base
+func sweepCombinations(base *models.HybridParams, grid models.SweepGrid) ([]*models.HybridParams, error) {
+	start := models.HybridParams{}
+	if base != nil {
+		start = *base
+	}
+	combos := []*models.HybridParams{&start}
+
+	// expand nhân mỗi combination với các giá trị của 1 tham số
+	expand := func(n int, set func(p *models.HybridParams, i int)) {
+		if n == 0 {
+			return
+		}
+		next := make([]*models.HybridParams, 0, len(combos)*n)
+		for _, c := range combos {
+			for i := 0; i < n; i++ {
+				p := *c
+				set(&p, i)
+				next = append(next, &p)
+			}
+		}
+		combos = next
+	}
+
+	floats := []struct {
+		name string
+		r    *models.SweepRange
+		set  func(p *models.HybridParams, v float64)
+	}{
+		{"alpha", grid.Alpha, func(p *models.HybridParams, v float64) { p.Alpha = &v }},
+		{"beta", grid.Beta, func(p *models.HybridParams, v float64) { p.Beta = &v }},
+		{"gamma", grid.Gamma, func(p *models.HybridParams, v float64) { p.Gamma = &v }},
+		{"debt_half_life", grid.DebtHalfLife, func(p *models.HybridParams, v float64) { p.DebtHalfLife = &v }},
+	}
+	for _, f := range floats {
+		values, err := rangeValues(f.name, f.r)
+		if err != nil {
+			return nil, err
+		}
+		expand(len(values), func(p *models.HybridParams, i int) { f.set(p, values[i]) })
+	}
+
+	windows, err := rangeValues("debt_window", grid.DebtWindow)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range windows {
+		if v != math.Trunc(v) || v < 1 {
+			return nil, fmt.Errorf("%w: debt_window values must be whole ticks >= 1", utils.ErrInvalidRequest)
+		}
+	}
+	expand(len(windows), func(p *models.HybridParams, i int) {
+		w := int(windows[i])
+		p.DebtWindow = &w
+	})
+
+	expand(len(grid.WeightedDebt), func(p *models.HybridParams, i int) {
+		p.WeightedDebt = &grid.WeightedDebt[i]
+	})
+	expand(len(grid.DebtDecay), func(p *models.HybridParams, i int) {
+		p.DebtDecay = &grid.DebtDecay[i]
+	})
+
+	return combos, nil
+}
+
+// rangeValues lists the values of r, nil when the parameter is not swept
+func rangeValues(name string, r *models.SweepRange) ([]float64, error) {
+	if r == nil {
+		return nil, nil
+	}
+	if len(r.Values) > 0 {
+		return r.Values, nil
+	}
+
+	n := int(math.Floor((r.To-r.From)/r.Step+1e-9)) + 1
+	if n > maxRangeValues {
+		return nil, fmt.Errorf("%w: %s range has %d values, max is %d", utils.ErrInvalidRequest, name, n, maxRangeValues)
+	}
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = r.From + float64(i)*r.Step
+	}
+	return values, nil
+}
+
+// prioritySatisfaction is Σ 1/Priority over allocated requests divided by
+// the same sum over all requests
+func prioritySatisfaction(run runWorkload, events []models.Event) float64 {
+	var total, served float64
+	for _, r := range run.requests {
+		total += 1 / float64(r.Priority)
+	}
+	for _, e := range events {
+		if e.Action == models.EventAllocated {
+			served += 1 / float64(e.Priority)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return served / total
+}
+
+// markPareto flags the results no other result dominates on
+// (FairnessIndex, PrioritySatisfaction), both maximised
+func markPareto(results []models.SweepResult) {
+	for i := range results {
+		a := &results[i]
+		a.Pareto = true
+		for j, b := range results {
+			if i == j {
+				continue
+			}
+			if b.FairnessIndex >= a.FairnessIndex && b.PrioritySatisfaction >= a.PrioritySatisfaction &&
+				(b.FairnessIndex > a.FairnessIndex || b.PrioritySatisfaction > a.PrioritySatisfaction) {
+				a.Pareto = false
+				break
+			}
+		}
+	}
+}
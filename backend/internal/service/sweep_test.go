@@ -0,0 +1,148 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/eddiekhean/high-contention-resource-allocation-backend/internal/models"
+)
+
+func TestRangeValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *models.SweepRange
+		want    []float64
+		wantErr string
+	}{
+		{name: "not swept", r: nil, want: nil},
+		{name: "explicit values", r: &models.SweepRange{Values: []float64{3, 1}, Step: 1, To: 10}, want: []float64{3, 1}},
+		{name: "from to step", r: &models.SweepRange{From: 0, To: 1, Step: 0.25}, want: []float64{0, 0.25, 0.5, 0.75, 1}},
+		// 0.1 không biểu diễn chính xác, vẫn phải lấy được 0.3
+		{name: "float step", r: &models.SweepRange{From: 0, To: 0.3, Step: 0.1}, want: []float64{0, 0.1, 0.2, 0.3}},
+		{name: "single value", r: &models.SweepRange{From: 2, To: 2, Step: 1}, want: []float64{2}},
+		{name: "too many", r: &models.SweepRange{From: 0, To: 1000, Step: 1}, wantErr: "alpha range has 1001 values, max is 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rangeValues("alpha", tt.r)
+			if !checkErr(t, err, tt.wantErr) {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSweepCombinations(t *testing.T) {
+	gamma := 2.0
+	base := &models.HybridParams{Gamma: &gamma}
+
+	combos, err := sweepCombinations(base, models.SweepGrid{
+		Alpha:      &models.SweepRange{Values: []float64{1, 2}},
+		Beta:       &models.SweepRange{From: 0, To: 1, Step: 0.5},
+		DebtWindow: &models.SweepRange{Values: []float64{5}},
+		DebtDecay:  []string{"none", "window"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(combos) != 2*3*1*2 {
+		t.Fatalf("got %d combinations, want 12", len(combos))
+	}
+
+	seen := map[[3]float64]int{}
+	for _, c := range combos {
+		if c.Gamma == nil || *c.Gamma != 2 {
+			t.Errorf("gamma = %v, want the base value 2", c.Gamma)
+		}
+		if c.DebtWindow == nil || *c.DebtWindow != 5 {
+			t.Errorf("debt_window = %v, want 5", c.DebtWindow)
+		}
+		if c.WeightedDebt != nil || c.DebtHalfLife != nil {
+			t.Error("parameters that are not swept must stay unset")
+		}
+		decay := 0.0
+		if *c.DebtDecay == "window" {
+			decay = 1
+		}
+		seen[[3]float64{*c.Alpha, *c.Beta, decay}]++
+	}
+	if len(seen) != 12 {
+		t.Errorf("got %d distinct combinations, want 12: %v", len(seen), seen)
+	}
+
+	combos, err = sweepCombinations(nil, models.SweepGrid{})
+	if err != nil || len(combos) != 1 || combos[0].Alpha != nil {
+		t.Errorf("empty grid: got %v, %v, want one combination of the defaults", combos, err)
+	}
+
+	_, err = sweepCombinations(nil, models.SweepGrid{DebtWindow: &models.SweepRange{Values: []float64{2.5}}})
+	checkErr(t, err, "debt_window values must be whole ticks >= 1")
+}
+
+func TestPrioritySatisfaction(t *testing.T) {
+	run := runWorkload{requests: []models.Request{
+		{ID: 1, Priority: 1},
+		{ID: 2, Priority: 2},
+		{ID: 3, Priority: 4},
+	}}
+
+	tests := []struct {
+		name   string
+		events []models.Event
+		want   float64
+	}{
+		{name: "nothing allocated", want: 0},
+		{
+			name: "only the vip",
+			events: []models.Event{
+				{RequestID: 1, Priority: 1, Action: models.EventAllocated},
+				{RequestID: 2, Priority: 2, Action: models.EventRejected},
+			},
+			want: 1 / 1.75,
+		},
+		{
+			name: "everyone",
+			events: []models.Event{
+				{RequestID: 1, Priority: 1, Action: models.EventAllocated},
+				{RequestID: 2, Priority: 2, Action: models.EventAllocated},
+				{RequestID: 3, Priority: 4, Action: models.EventAllocated},
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prioritySatisfaction(run, tt.events); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkPareto(t *testing.T) {
+	results := []models.SweepResult{
+		{FairnessIndex: 0.9, PrioritySatisfaction: 0.5},
+		{FairnessIndex: 0.6, PrioritySatisfaction: 0.8},
+		{FairnessIndex: 0.6, PrioritySatisfaction: 0.4}, // bị cả 2 kết quả đầu dominate
+		{FairnessIndex: 0.9, PrioritySatisfaction: 0.5}, // bằng nhau thì không dominate
+		{FairnessIndex: 0.5, PrioritySatisfaction: 0.8}, // kém hơn ở fairness
+	}
+	markPareto(results)
+
+	want := []bool{true, true, false, true, false}
+	for i, r := range results {
+		if r.Pareto != want[i] {
+			t.Errorf("result %d: pareto = %v, want %v", i, r.Pareto, want[i])
+		}
+	}
+}
@@ -22,6 +22,16 @@ func slotKey(simulationID string) string {
 	return fmt.Sprintf("simulation:%s:slots", simulationID)
 }
 
+// redis key: simulation:{id}:pools:{pool}:slots
+func poolKey(simulationID, pool string) string {
+	return fmt.Sprintf("simulation:%s:pools:%s:slots", simulationID, pool)
+}
+
+// redis key: simulation:{id}:resources:{resource}
+func resourceKey(simulationID, resource string) string {
+	return fmt.Sprintf("simulation:%s:resources:%s", simulationID, resource)
+}
+
 // InitSlot khởi tạo số slot ban đầu cho 1 simulation
 // Chỉ gọi 1 lần khi start simulation
 func (s *SlotStore) InitSlot(
@@ -29,15 +39,37 @@ func (s *SlotStore) InitSlot(
 	simulationID string,
 	slots int,
 ) error {
-	key := slotKey(simulationID)
+	return s.initKey(ctx, slotKey(simulationID), slots, simulationID)
+}
+
+// InitPool khởi tạo 1 pool (SKU) của simulation
+func (s *SlotStore) InitPool(
+	ctx context.Context,
+	simulationID string,
+	pool string,
+	slots int,
+) error {
+	return s.initKey(ctx, poolKey(simulationID, pool), slots, simulationID+"/"+pool)
+}
+
+// InitResource khởi tạo capacity của 1 resource (cpu, gpu, ...)
+func (s *SlotStore) InitResource(
+	ctx context.Context,
+	simulationID string,
+	resource string,
+	capacity int,
+) error {
+	return s.initKey(ctx, resourceKey(simulationID, resource), capacity, simulationID+"/"+resource)
+}
 
+func (s *SlotStore) initKey(ctx context.Context, key string, slots int, name string) error {
 	ok, err := s.rdb.SetNX(ctx, key, slots, 0).Result()
 	if err != nil {
 		return err
 	}
 
 	if !ok {
-		return fmt.Errorf("slot already initialized for simulation %s", simulationID)
+		return fmt.Errorf("slot already initialized for simulation %s", name)
 	}
 
 	return nil
@@ -50,8 +82,20 @@ func (s *SlotStore) TryAcquire(
 	simulationID string,
 	n int,
 ) (bool, error) {
-	key := slotKey(simulationID)
+	return s.tryAcquireKey(ctx, slotKey(simulationID), n)
+}
 
+// TryAcquirePool giống TryAcquire nhưng trên 1 pool
+func (s *SlotStore) TryAcquirePool(
+	ctx context.Context,
+	simulationID string,
+	pool string,
+	n int,
+) (bool, error) {
+	return s.tryAcquireKey(ctx, poolKey(simulationID, pool), n)
+}
+
+func (s *SlotStore) tryAcquireKey(ctx context.Context, key string, n int) (bool, error) {
 	val, err := s.rdb.DecrBy(ctx, key, int64(n)).Result()
 	if err != nil {
 		return false, err
@@ -66,6 +110,87 @@ func (s *SlotStore) TryAcquire(
 	return true, nil
 }
 
+// acquireUpToScript chiếm min(còn lại, n) slot trong 1 lệnh atomic
+var acquireUpToScript = redis.NewScript(`
+local left = tonumber(redis.call("GET", KEYS[1]) or "0")
+local n = tonumber(ARGV[1])
+if left < n then
+	n = left
+end
+if n > 0 then
+	redis.call("DECRBY", KEYS[1], n)
+else
+	n = 0
+end
+return n
+`)
+
+// AcquireUpTo chiếm tối đa n slot (partial fill)
+// return số slot chiếm được, 0 nếu đã hết
+func (s *SlotStore) AcquireUpTo(
+	ctx context.Context,
+	simulationID string,
+	n int,
+) (int, error) {
+	return s.acquireUpToKey(ctx, slotKey(simulationID), n)
+}
+
+// AcquireUpToPool giống AcquireUpTo nhưng trên 1 pool
+func (s *SlotStore) AcquireUpToPool(
+	ctx context.Context,
+	simulationID string,
+	pool string,
+	n int,
+) (int, error) {
+	return s.acquireUpToKey(ctx, poolKey(simulationID, pool), n)
+}
+
+func (s *SlotStore) acquireUpToKey(ctx context.Context, key string, n int) (int, error) {
+	granted, err := acquireUpToScript.Run(ctx, s.rdb, []string{key}, n).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	return granted, nil
+}
+
+// acquireVectorScript chiếm cả vector hoặc không chiếm gì (atomic)
+var acquireVectorScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	if tonumber(redis.call("GET", key) or "0") < tonumber(ARGV[i]) then
+		return 0
+	end
+end
+for i, key in ipairs(KEYS) do
+	redis.call("DECRBY", key, ARGV[i])
+end
+return 1
+`)
+
+// TryAcquireResources chiếm demand[r] của mọi resource r, all-or-nothing
+func (s *SlotStore) TryAcquireResources(
+	ctx context.Context,
+	simulationID string,
+	demand map[string]int,
+) (bool, error) {
+	keys := make([]string, 0, len(demand))
+	args := make([]any, 0, len(demand))
+	for resource, amount := range demand {
+		keys = append(keys, resourceKey(simulationID, resource))
+		args = append(args, amount)
+	}
+	if len(keys) == 0 {
+		return true, nil
+	}
+
+	ok, err := acquireVectorScript.Run(ctx, s.rdb, keys, args...).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return ok == 1, nil
+}
+
 // Release trả slot lại
 func (s *SlotStore) Release(
 	ctx context.Context,
@@ -82,3 +207,35 @@ func (s *SlotStore) Clear(
 	key := slotKey(simulationID)
 	return s.rdb.Del(ctx, key).Err()
 }
+
+// ClearPools xoá các pool của simulation
+func (s *SlotStore) ClearPools(
+	ctx context.Context,
+	simulationID string,
+	pools []string,
+) error {
+	keys := make([]string, 0, len(pools))
+	for _, pool := range pools {
+		keys = append(keys, poolKey(simulationID, pool))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.rdb.Del(ctx, keys...).Err()
+}
+
+// ClearResources xoá các resource của simulation
+func (s *SlotStore) ClearResources(
+	ctx context.Context,
+	simulationID string,
+	resources []string,
+) error {
+	keys := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		keys = append(keys, resourceKey(simulationID, resource))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.rdb.Del(ctx, keys...).Err()
+}
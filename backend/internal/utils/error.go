@@ -85,6 +85,14 @@ func msgForTag(fe validator.FieldError) string {
 		return fmt.Sprintf("Must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
 	case "gt":
 		return fmt.Sprintf("Must be greater than %s", fe.Param())
+	case "gte":
+		return fmt.Sprintf("Must be at least %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("Must be at most %s", fe.Param())
+	case "unique":
+		return "Must not contain duplicates"
+	case "required_without_all":
+		return fmt.Sprintf("This field is required unless one of %s is set", strings.ReplaceAll(fe.Param(), " ", ", "))
 	default:
 		return fe.Error() // Default formatted error
 	}